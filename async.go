@@ -0,0 +1,313 @@
+package excel
+
+import (
+	"context"
+	"io"
+	"reflect"
+	"sync"
+
+	"github.com/panjf2000/ants"
+	"github.com/pkg/errors"
+	"github.com/xuri/excelize/v2"
+)
+
+const (
+	_defaultAsyncScanBufferSize = 64
+)
+
+/*
+*
+RowIterator iterate rows one by one, so that rows needn't be loaded into memory at once
+*/
+type RowIterator interface {
+	// Next prepare the next row, it returns false when there is no more row or an error occurs
+	Next() bool
+	// Row return the current row and its row index (begin from 1) in sheet
+	Row() (rowIndex int, row []string)
+	// Err return the error occurred during iteration
+	Err() error
+}
+
+type sliceRowIterator struct {
+	rows          [][]string
+	rowIndexStart int
+	cur           int
+}
+
+/*
+*
+NewRowIterator return a RowIterator of rows, rowIndexStart is the row index (begin from 1) of rows[0] in sheet
+*/
+func NewRowIterator(rows [][]string, rowIndexStart int) RowIterator {
+	return &sliceRowIterator{rows: rows, rowIndexStart: rowIndexStart, cur: -1}
+}
+
+func (it *sliceRowIterator) Next() bool {
+	it.cur++
+	return it.cur < len(it.rows)
+}
+
+func (it *sliceRowIterator) Row() (int, []string) {
+	return it.rowIndexStart + it.cur, it.rows[it.cur]
+}
+
+func (it *sliceRowIterator) Err() error {
+	return nil
+}
+
+/*
+*
+sheetRowIterator iterate the data rows of sheet by streaming, the header rows and empty rows are skipped
+*/
+type sheetRowIterator struct {
+	sheet         string
+	progress      *progressReporter
+	rows          *excelize.Rows
+	rowBeginIndex int
+	// body merge cells which are filled into rows, nil unless option FillMergedBodyCells is set
+	body     []cellRange
+	rowIndex int
+	row      []string
+	err      error
+}
+
+func (it *sheetRowIterator) Next() bool {
+	if it.rows == nil {
+		return false
+	}
+	for it.rows.Next() {
+		it.rowIndex++
+		if it.rowIndex <= it.rowBeginIndex {
+			continue
+		}
+		if it.row, it.err = it.rows.Columns(); it.err != nil {
+			break
+		}
+		it.fillMergedCells()
+		if len(it.row) != 0 {
+			it.progress.rowRead(it.sheet)
+			return true
+		}
+	}
+	it.progress.sheetDone(it.sheet)
+
+	if err := it.Close(); err != nil && it.err == nil {
+		it.err = err
+	}
+	return false
+}
+
+/*
+*
+Close close the rows of sheet, it's called by Next at the end of rows, or by the scanner when the scanning stops early
+*/
+func (it *sheetRowIterator) Close() error {
+	if it.rows == nil {
+		return nil
+	}
+	err := it.rows.Close()
+	it.rows = nil
+	return err
+}
+
+/*
+*
+fillMergedCells set the value of body merge cells to the current row which they span
+*/
+func (it *sheetRowIterator) fillMergedCells() {
+	for _, r := range it.body {
+		if it.rowIndex < r.rowIndexStart || it.rowIndex > r.rowIndexEnd {
+			continue
+		}
+		for len(it.row) < r.colIndexEnd {
+			it.row = append(it.row, "")
+		}
+		for j := r.colIndexStart - 1; j < r.colIndexEnd; j++ {
+			it.row[j] = r.value
+		}
+	}
+}
+
+func (it *sheetRowIterator) Row() (int, []string) {
+	return it.rowIndex, it.row
+}
+
+func (it *sheetRowIterator) Err() error {
+	return it.err
+}
+
+/*
+*
+SheetRowIterator return a RowIterator which streams the data rows of sheet,
+the body merge cells are filled into the rows they span if option FillMergedBodyCells is set
+*/
+func (e *Excel) SheetRowIterator(sheet string) (RowIterator, error) {
+	importer := e.SheetImporter(sheet)
+	if importer == nil {
+		return nil, errors.Errorf("sheet %s is not active", sheet)
+	}
+	it := &sheetRowIterator{
+		sheet:         sheet,
+		progress:      e.progress,
+		rowBeginIndex: importer.getRowsBeginIndex(),
+	}
+	if e.fillMergedBody {
+		var err error
+		if it.body, err = e.getBodyMergeCells(sheet, it.rowBeginIndex); err != nil {
+			return nil, errors.Wrap(err, "e.getBodyMergeCells")
+		}
+	}
+
+	rows, err := e.file.Rows(sheet)
+	if err != nil {
+		return nil, errors.Wrap(err, "e.file.Rows")
+	}
+	it.rows = rows
+	return it, nil
+}
+
+/*
+*
+AsyncScanExRowsContext scan rows to resps async, every row produces exactly one result with its row index,
+the rows in flight are bounded by worker nums and buffer size, and the results keep the order of rows if
+option AsyncScanOrdered is set. The workers are released and the channel is closed when all rows are scanned
+or ctx is done. If the iterator fails, the last result carries its error. The iterator is closed when the scanning
+stops if it implements io.Closer, such as the iterator of SheetRowIterator
+*/
+func (root *Importer) AsyncScanExRowsContext(ctx context.Context, rows RowIterator, resps ...interface{}) (<-chan *AsyncScanExRes, error) {
+	return root.asyncScanExRowsContext(ctx, rows, root.asyncScanOrdered, resps...)
+}
+
+func (root *Importer) asyncScanExRowsContext(ctx context.Context, rows RowIterator, ordered bool, resps ...interface{}) (<-chan *AsyncScanExRes, error) {
+	workerNums := root.asyncScanWorkerNums
+	if workerNums <= 0 {
+		workerNums = _defaultAsyncScanExRowsGoroutineNums
+	}
+	bufferSize := root.asyncScanBufferSize
+	if bufferSize <= 0 {
+		bufferSize = _defaultAsyncScanBufferSize
+	}
+	pool, err := ants.NewPool(workerNums)
+	if err != nil {
+		return nil, errors.Wrap(err, "ants.NewPool")
+	}
+	// init the leaf nodes before workers share them
+	if len(root.leafNodes) == 0 {
+		root.leafNodes = root.getLeafNodes()
+	}
+
+	out := make(chan *AsyncScanExRes, bufferSize)
+	send := func(ch chan<- *AsyncScanExRes, res *AsyncScanExRes) bool {
+		select {
+		case ch <- res:
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}
+
+	// pending keep the result channel of every row in order when ordered
+	var (
+		pending       chan chan *AsyncScanExRes
+		collectorDone chan struct{}
+	)
+	enqueue := func(done chan *AsyncScanExRes) bool {
+		select {
+		case pending <- done:
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}
+	if ordered {
+		pending = make(chan chan *AsyncScanExRes, bufferSize)
+		collectorDone = make(chan struct{})
+		go func() {
+			defer close(collectorDone)
+			for done := range pending {
+				select {
+				case res := <-done:
+					if !send(out, res) {
+						return
+					}
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+	}
+
+	go func() {
+		var wg sync.WaitGroup
+		defer func() {
+			// the rows of sheet are left open if ctx is done before the end of rows
+			if closer, ok := rows.(io.Closer); ok {
+				_ = closer.Close()
+			}
+			wg.Wait()
+			if pending != nil {
+				close(pending)
+				<-collectorDone
+			}
+			// the final progress is reported before the consumer sees the end of results
+			root.progress.flush()
+			close(out)
+			pool.Release()
+		}()
+
+		for rows.Next() {
+			if ctx.Err() != nil {
+				return
+			}
+			rowIndex, row := rows.Row()
+
+			ch := (chan<- *AsyncScanExRes)(out)
+			if pending != nil {
+				done := make(chan *AsyncScanExRes, 1)
+				if !enqueue(done) {
+					return
+				}
+				ch = done
+			}
+
+			wg.Add(1)
+			if err := pool.Submit(func() {
+				defer wg.Done()
+				respParams := make([]interface{}, 0, len(resps))
+				for _, resp := range resps {
+					respParams = append(respParams, reflect.New(reflect.Indirect(reflect.ValueOf(resp).Elem()).Type()).Interface())
+				}
+				res := root.scanRowAll(rowIndex, row, respParams)
+				root.progress.rowScanned(res.Err != nil)
+				send(ch, res)
+			}); err != nil {
+				wg.Done()
+				send(ch, &AsyncScanExRes{RowIndex: rowIndex, Err: errors.Wrap(err, "pool.Submit")})
+				return
+			}
+		}
+
+		if err := rows.Err(); err != nil {
+			wg.Wait()
+			res := &AsyncScanExRes{Err: errors.Wrap(err, "rows iterator")}
+			if pending != nil {
+				done := make(chan *AsyncScanExRes, 1)
+				done <- res
+				enqueue(done)
+				return
+			}
+			send(out, res)
+		}
+	}()
+
+	return out, nil
+}
+
+/*
+*
+AsyncScanExRowsContext scan rows of the active sheet to resps async, see Importer.AsyncScanExRowsContext
+*/
+func (e *Excel) AsyncScanExRowsContext(ctx context.Context, rows RowIterator, resps ...interface{}) (<-chan *AsyncScanExRes, error) {
+	importer := e.importers[_defaultSheetIndex]
+	return importer.AsyncScanExRowsContext(ctx, rows, resps...)
+}
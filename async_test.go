@@ -0,0 +1,119 @@
+package excel
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+var errIterator = errors.New("broken row")
+
+// failingRowIterator fails after its rows
+type failingRowIterator struct {
+	RowIterator
+}
+
+func (it *failingRowIterator) Err() error {
+	return errIterator
+}
+
+func newAsyncRows(n int) [][]string {
+	rows := make([][]string, n)
+	for i := range rows {
+		rows[i] = []string{fmt.Sprintf("HT-%03d", i), fmt.Sprint(i), fmt.Sprint(i)}
+	}
+	return rows
+}
+
+func TestAsyncScanExRowsContextOrdered(t *testing.T) {
+	e := newTestExcel(t, _asyncPaths, nil, AsyncScanOrdered(true), AsyncScanWorkerNums(4), AsyncScanBufferSize(2))
+	rows := newAsyncRows(100)
+
+	ch, err := e.AsyncScanExRowsContext(context.Background(), NewRowIterator(rows, 3), &asyncRow{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	res := collectAsyncRes(t, ch)
+	if len(res) != len(rows) {
+		t.Fatalf("expect %d results, got %d", len(rows), len(res))
+	}
+	for i, r := range res {
+		if r.Err != nil {
+			t.Fatal(r.Err)
+		}
+		if r.RowIndex != i+3 || r.Resps[0].(*asyncRow).Number.GetValue() != rows[i][0] {
+			t.Fatalf("expect row %d %s at %d, got row %d %+v", i+3, rows[i][0], i, r.RowIndex, r.Resps[0])
+		}
+	}
+}
+
+func TestAsyncScanExRowsContextErrors(t *testing.T) {
+	rows := [][]string{{"HT-001", "10", "1"}, {"HT-002", "x", "2"}, {"HT-003", "30", "3"}}
+	for _, ordered := range []bool{false, true} {
+		t.Run(fmt.Sprintf("ordered=%v", ordered), func(t *testing.T) {
+			e := newTestExcel(t, _asyncPaths, nil, AsyncScanOrdered(ordered))
+			ch, err := e.AsyncScanExRowsContext(context.Background(), &failingRowIterator{NewRowIterator(rows, 3)}, &asyncRow{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			res := collectAsyncRes(t, ch)
+			if len(res) != len(rows)+1 {
+				t.Fatalf("expect a result per row and the iterator error, got %d results", len(res))
+			}
+
+			// the iterator error is the last result
+			last := res[len(res)-1]
+			if last.RowIndex != 0 || !errors.Is(last.Err, errIterator) {
+				t.Fatalf("expect the iterator error at last, got %+v", last)
+			}
+			var failed []int
+			for _, r := range res[:len(rows)] {
+				if r.Err != nil {
+					var scanErrs ScanErrors
+					if !errors.As(r.Err, &scanErrs) || r.Resps != nil {
+						t.Fatalf("expect scan errors without resps, got %+v", r)
+					}
+					failed = append(failed, r.RowIndex)
+				}
+			}
+			if len(failed) != 1 || failed[0] != 4 {
+				t.Fatalf("expect row 4 failed, got %v", failed)
+			}
+		})
+	}
+}
+
+func TestAsyncScanExRowsContextCancel(t *testing.T) {
+	data := make([][]interface{}, 500)
+	for i := range data {
+		data[i] = []interface{}{fmt.Sprintf("HT-%03d", i), i, i}
+	}
+	for _, ordered := range []bool{false, true} {
+		t.Run(fmt.Sprintf("ordered=%v", ordered), func(t *testing.T) {
+			e := newTestExcel(t, _asyncPaths, data, AsyncScanOrdered(ordered), AsyncScanWorkerNums(1), AsyncScanBufferSize(1))
+			it, err := e.SheetRowIterator(e.activeSheetNames[0])
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			ch, err := e.AsyncScanExRowsContext(ctx, it, &asyncRow{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if first := <-ch; first == nil || first.Err != nil {
+				t.Fatalf("unexpected first result %+v", first)
+			}
+			cancel()
+
+			if res := collectAsyncRes(t, ch); len(res)+1 >= len(data) {
+				t.Fatalf("expect the scanning stops early, got %d results", len(res)+1)
+			}
+			if it.(*sheetRowIterator).rows != nil {
+				t.Fatal("the rows of sheet are not closed after ctx is done")
+			}
+		})
+	}
+}
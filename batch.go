@@ -0,0 +1,196 @@
+package excel
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	_defaultBatchSize = 500
+)
+
+type ErrorPolicy int
+
+const (
+	// ErrorPolicyStop stop at the first row which failed to scan and return its error
+	ErrorPolicyStop ErrorPolicy = iota
+	// ErrorPolicySkip skip the rows which failed to scan
+	ErrorPolicySkip
+	// ErrorPolicyCollect skip the rows which failed to scan and return all their errors as RowErrors at last
+	ErrorPolicyCollect
+)
+
+/*
+*
+RowError a row which failed to scan
+*/
+type RowError struct {
+	Sheet string
+	Row   int
+	Err   error
+}
+
+func (e *RowError) Error() string {
+	return fmt.Sprintf("sheet:(%s) row:(%d) %v", e.Sheet, e.Row, e.Err)
+}
+
+/*
+*
+RowErrors all rows which failed to scan
+*/
+type RowErrors []*RowError
+
+func (es RowErrors) Error() string {
+	msgs := make([]string, 0, len(es))
+	for _, e := range es {
+		msgs = append(msgs, e.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+/*
+*
+BatchMeta the meta info of a batch, it also reports the progress of ForEachRow
+*/
+type BatchMeta struct {
+	// Sheet is the sheet of the batch rows
+	Sheet string
+	// Index is the index of the batch, begin from 0
+	Index int
+	// RowIndices are the row indices (begin from 1) of the batch rows in sheet
+	RowIndices []int
+	// ScannedRows is the count of rows scanned so far, including the failed rows
+	ScannedRows int
+	// FailedRows is the count of rows failed to scan so far
+	FailedRows int
+}
+
+type batchOptions struct {
+	batchSize   int
+	errorPolicy ErrorPolicy
+}
+
+type BatchOption func(*batchOptions)
+
+/*
+*
+BatchSize set the max row count of a batch
+*/
+func BatchSize(batchSize int) BatchOption {
+	return func(o *batchOptions) {
+		o.batchSize = batchSize
+	}
+}
+
+/*
+*
+OnRowError set the policy for the rows which failed to scan, default is ErrorPolicyStop
+*/
+func OnRowError(policy ErrorPolicy) BatchOption {
+	return func(o *batchOptions) {
+		o.errorPolicy = policy
+	}
+}
+
+/*
+*
+ForEachRow scan the data rows of all active sheets to T and call fn with batches of them in the order of rows,
+rows are scanned by the workers set by option AsyncScanWorkerNums, it stops when fn returns error or ctx is done
+ex: ForEachRow(ctx, e, func(batch []*BaseInfo, meta BatchMeta) error { return db.Insert(batch) }, BatchSize(500))
+*/
+func ForEachRow[T any](ctx context.Context, e *Excel, fn func(batch []*T, meta BatchMeta) error, opts ...BatchOption) (err error) {
+	o := &batchOptions{batchSize: _defaultBatchSize}
+	for _, opt := range opts {
+		opt(o)
+	}
+	if o.batchSize <= 0 {
+		o.batchSize = _defaultBatchSize
+	}
+
+	var (
+		meta    BatchMeta
+		rowErrs RowErrors
+	)
+	for _, sheet := range e.activeSheetNames {
+		meta.Sheet = sheet
+		if err = forEachSheetRow(ctx, e, sheet, fn, o, &meta, &rowErrs); err != nil {
+			return
+		}
+	}
+
+	if len(rowErrs) != 0 {
+		return rowErrs
+	}
+	return ctx.Err()
+}
+
+func forEachSheetRow[T any](ctx context.Context, e *Excel, sheet string, fn func(batch []*T, meta BatchMeta) error,
+	o *batchOptions, meta *BatchMeta, rowErrs *RowErrors) (err error) {
+	rows, err := e.SheetRowIterator(sheet)
+	if err != nil {
+		return errors.Wrap(err, "e.SheetRowIterator")
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	ch, err := e.SheetImporter(sheet).asyncScanExRowsContext(ctx, rows, true, new(T))
+	if err != nil {
+		cancel()
+		return errors.Wrap(err, "asyncScanExRowsContext")
+	}
+	defer func() {
+		cancel()
+		// wait for the workers to exit
+		for range ch {
+		}
+	}()
+
+	batch := make([]*T, 0, o.batchSize)
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		if err := fn(batch, *meta); err != nil {
+			return err
+		}
+		meta.Index++
+		meta.RowIndices = nil
+		batch = make([]*T, 0, o.batchSize)
+		return nil
+	}
+
+	for res := range ch {
+		if res.RowIndex == 0 {
+			// the iterator failed
+			return res.Err
+		}
+
+		meta.ScannedRows++
+		if res.Err != nil {
+			meta.FailedRows++
+			rowErr := &RowError{Sheet: sheet, Row: res.RowIndex, Err: res.Err}
+			switch o.errorPolicy {
+			case ErrorPolicyStop:
+				return rowErr
+			case ErrorPolicyCollect:
+				*rowErrs = append(*rowErrs, rowErr)
+			}
+			continue
+		}
+
+		batch = append(batch, res.Resps[0].(*T))
+		meta.RowIndices = append(meta.RowIndices, res.RowIndex)
+		if len(batch) == o.batchSize {
+			if err = flush(); err != nil {
+				return
+			}
+		}
+	}
+
+	if err = ctx.Err(); err != nil {
+		return
+	}
+	return flush()
+}
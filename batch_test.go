@@ -0,0 +1,130 @@
+package excel
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+var errBatch = errors.New("insert failed")
+
+type batchCall struct {
+	numbers []string
+	meta    BatchMeta
+}
+
+func collectBatches(t *testing.T, e *Excel, opts ...BatchOption) (calls []batchCall, err error) {
+	t.Helper()
+	err = ForEachRow(context.Background(), e, func(batch []*asyncRow, meta BatchMeta) error {
+		call := batchCall{meta: meta}
+		for _, row := range batch {
+			call.numbers = append(call.numbers, row.Number.GetValue().(string))
+		}
+		calls = append(calls, call)
+		return nil
+	}, opts...)
+	return
+}
+
+func TestForEachRowBatches(t *testing.T) {
+	var data [][]interface{}
+	for _, number := range []string{"HT-1", "HT-2", "HT-3", "HT-4", "HT-5", "HT-6", "HT-7"} {
+		data = append(data, []interface{}{number, 10, 1})
+	}
+	e := newTestExcel(t, _asyncPaths, data, AsyncScanWorkerNums(4))
+
+	calls, err := collectBatches(t, e, BatchSize(3))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sheet := e.activeSheetNames[0]
+	want := []batchCall{
+		{[]string{"HT-1", "HT-2", "HT-3"}, BatchMeta{Sheet: sheet, Index: 0, RowIndices: []int{3, 4, 5}, ScannedRows: 3}},
+		{[]string{"HT-4", "HT-5", "HT-6"}, BatchMeta{Sheet: sheet, Index: 1, RowIndices: []int{6, 7, 8}, ScannedRows: 6}},
+		{[]string{"HT-7"}, BatchMeta{Sheet: sheet, Index: 2, RowIndices: []int{9}, ScannedRows: 7}},
+	}
+	if !reflect.DeepEqual(calls, want) {
+		t.Fatalf("expect batches %+v, got %+v", want, calls)
+	}
+}
+
+func TestForEachRowErrorPolicy(t *testing.T) {
+	data := [][]interface{}{{"HT-1", 10, 1}, {"HT-2", "x", 2}, {"HT-3", 30, 3}, {"HT-4", 40, "y"}}
+	e := newTestExcel(t, _asyncPaths, data)
+
+	t.Run("stop", func(t *testing.T) {
+		calls, err := collectBatches(t, e)
+		var rowErr *RowError
+		if !errors.As(err, &rowErr) || rowErr.Row != 4 {
+			t.Fatalf("expect the error of row 4, got %v", err)
+		}
+		if len(calls) != 0 {
+			t.Fatalf("expect no batch before the failed row is handled, got %+v", calls)
+		}
+	})
+
+	t.Run("collect", func(t *testing.T) {
+		calls, err := collectBatches(t, e, OnRowError(ErrorPolicyCollect))
+		var rowErrs RowErrors
+		if !errors.As(err, &rowErrs) || len(rowErrs) != 2 || rowErrs[0].Row != 4 || rowErrs[1].Row != 6 {
+			t.Fatalf("expect the errors of row 4 and 6, got %v", err)
+		}
+		if len(calls) != 1 || !reflect.DeepEqual(calls[0].numbers, []string{"HT-1", "HT-3"}) {
+			t.Fatalf("expect the rows which are scanned, got %+v", calls)
+		}
+		if meta := calls[0].meta; meta.ScannedRows != 4 || meta.FailedRows != 2 || !reflect.DeepEqual(meta.RowIndices, []int{3, 5}) {
+			t.Fatalf("unexpected batch meta %+v", meta)
+		}
+	})
+
+	t.Run("skip", func(t *testing.T) {
+		calls, err := collectBatches(t, e, OnRowError(ErrorPolicySkip))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(calls) != 1 || !reflect.DeepEqual(calls[0].numbers, []string{"HT-1", "HT-3"}) {
+			t.Fatalf("expect the rows which are scanned, got %+v", calls)
+		}
+	})
+}
+
+func TestForEachRowCallbackError(t *testing.T) {
+	var data [][]interface{}
+	for _, number := range []string{"HT-1", "HT-2", "HT-3", "HT-4", "HT-5"} {
+		data = append(data, []interface{}{number, 10, 1})
+	}
+	e := newTestExcel(t, _asyncPaths, data)
+
+	var calls int
+	err := ForEachRow(context.Background(), e, func(batch []*asyncRow, meta BatchMeta) error {
+		calls++
+		if meta.Index == 1 {
+			return errBatch
+		}
+		return nil
+	}, BatchSize(2))
+	if !errors.Is(err, errBatch) {
+		t.Fatalf("expect the error of callback, got %v", err)
+	}
+	if calls != 2 {
+		t.Fatalf("expect ForEachRow stops at the failed batch, got %d calls", calls)
+	}
+}
+
+func TestForEachRowFillMergedBodyCells(t *testing.T) {
+	data := [][]interface{}{{"HT-1", 10, 1}, {nil, 20, 2}, {"HT-2", 30, 3}}
+	e := newTestExcel(t, _asyncPaths, data, FillMergedBodyCells(true))
+	// HT-1 is merged over two rows
+	if err := e.file.MergeCell(e.activeSheetNames[0], "A3", "A4"); err != nil {
+		t.Fatal(err)
+	}
+
+	calls, err := collectBatches(t, e)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(calls) != 1 || !reflect.DeepEqual(calls[0].numbers, []string{"HT-1", "HT-1", "HT-2"}) {
+		t.Fatalf("expect the merged cell is filled into the rows it spans, got %+v", calls)
+	}
+}
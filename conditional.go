@@ -0,0 +1,154 @@
+package excel
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/xuri/excelize/v2"
+)
+
+const (
+	// _cellPlaceholder is replaced by the first data cell of the col in the formula of FormulaRule
+	_cellPlaceholder = "{cell}"
+)
+
+/*
+*
+ConditionalRule a rule of conditional format, the cells which match the rule are shown in style
+*/
+type ConditionalRule struct {
+	options excelize.ConditionalFormatOptions
+	style   *excelize.Style
+}
+
+/*
+*
+ValueRule match the cells whose value compares with value by criteria, criteria is one of ">", "<", ">=", "<=", "==", "!=",
+ex: ValueRule(">", "100000", &excelize.Style{Font: &excelize.Font{Bold: true}})
+*/
+func ValueRule(criteria, value string, style *excelize.Style) ConditionalRule {
+	return ConditionalRule{
+		options: excelize.ConditionalFormatOptions{Type: "cell", Criteria: criteria, Value: value},
+		style:   style,
+	}
+}
+
+/*
+*
+BetweenRule match the cells whose value is between minValue and maxValue
+*/
+func BetweenRule(minValue, maxValue string, style *excelize.Style) ConditionalRule {
+	return ConditionalRule{
+		options: excelize.ConditionalFormatOptions{Type: "cell", Criteria: "between", MinValue: minValue, MaxValue: maxValue},
+		style:   style,
+	}
+}
+
+/*
+*
+DuplicateRule match the cells whose value is duplicate in the col
+*/
+func DuplicateRule(style *excelize.Style) ConditionalRule {
+	return ConditionalRule{
+		options: excelize.ConditionalFormatOptions{Type: "duplicate", Criteria: "="},
+		style:   style,
+	}
+}
+
+/*
+*
+FormulaRule match the cells for which formula is true, {cell} in formula is the cell to check, ex: "{cell}>TODAY()"
+*/
+func FormulaRule(formula string, style *excelize.Style) ConditionalRule {
+	return ConditionalRule{
+		options: excelize.ConditionalFormatOptions{Type: "formula", Criteria: formula},
+		style:   style,
+	}
+}
+
+/*
+*
+DateBeforeTodayRule match the date cells before today, ex: the overdue contracts
+*/
+func DateBeforeTodayRule(style *excelize.Style) ConditionalRule {
+	return FormulaRule("AND(ISNUMBER({cell}),{cell}<TODAY())", style)
+}
+
+/*
+*
+conditionalFormat the conditional rules of the col of path
+*/
+type conditionalFormat struct {
+	path  string
+	rules []ConditionalRule
+}
+
+/*
+*
+setConditionalFormats set the conditional formats of option WithConditionalFormat to the data cells of sheets,
+the col is resolved from the ex path, blocks[idx] are the data rows of the sheet, so the group header and subtotal
+rows are not formatted, and a rule of a sheet covers all its blocks, ex: DuplicateRule compares the cells of all groups
+*/
+func (e *Excel) setConditionalFormats(typ reflect.Type, blocks [][]rowBlock) (err error) {
+	if len(e.conditionalFormats) == 0 {
+		return
+	}
+
+	fields := exportFields(typ)
+	// formats[i] are the style ids of the rules of e.conditionalFormats[i]
+	formats := make([][]int, len(e.conditionalFormats))
+	for i, cf := range e.conditionalFormats {
+		for _, rule := range cf.rules {
+			var format int
+			if format, err = e.file.NewConditionalStyle(rule.style); err != nil {
+				return errors.Wrap(err, "e.file.NewConditionalStyle")
+			}
+			formats[i] = append(formats[i], format)
+		}
+	}
+
+	for i, cf := range e.conditionalFormats {
+		col := fieldColIndex(fields, cf.path)
+		if col == -1 {
+			return errors.Errorf("no field of ex path %s", cf.path)
+		}
+		var colName string
+		if colName, err = excelize.ColumnNumberToName(col); err != nil {
+			return err
+		}
+
+		for idx, sheetBlocks := range blocks {
+			var firstCell string
+			refs := make([]string, 0, len(sheetBlocks))
+			for _, block := range sheetBlocks {
+				if block.last < block.first {
+					continue
+				}
+				if firstCell == "" {
+					firstCell = fmt.Sprintf("%s%d", colName, block.first)
+				}
+				refs = append(refs, fmt.Sprintf("%s%d:%s%d", colName, block.first, colName, block.last))
+			}
+			if len(refs) == 0 {
+				continue
+			}
+
+			opts := make([]excelize.ConditionalFormatOptions, 0, len(cf.rules))
+			for k, rule := range cf.rules {
+				opt := rule.options
+				opt.Format = formats[i][k]
+				if opt.Type == "formula" {
+					// the formula is relative to the first cell of the ranges
+					opt.Criteria = strings.ReplaceAll(opt.Criteria, _cellPlaceholder, firstCell)
+				}
+				opts = append(opts, opt)
+			}
+			if err = e.file.SetConditionalFormat(e.activeSheetNames[idx], strings.Join(refs, " "), opts); err != nil {
+				return errors.Wrap(err, "e.file.SetConditionalFormat")
+			}
+		}
+	}
+	return
+}
@@ -0,0 +1,147 @@
+package excel
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"unicode/utf8"
+
+	"github.com/pkg/errors"
+	"github.com/xuri/excelize/v2"
+	"golang.org/x/text/encoding/simplifiedchinese"
+	"golang.org/x/text/transform"
+)
+
+const (
+	_defaultCSVHeaderRow = 1
+	// _csvSniffSize the size of bytes to detect the encoding of csv
+	_csvSniffSize = 4096
+)
+
+var _utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+/*
+*
+NewExcelFromCSV create excel from csv or tsv (with option CSVComma('\t')), the first rows set by option HeaderRow
+(default 1) are the header, a header spans the following blank or repeated cells like option HeaderRow.
+The encoding is detected from the UTF-8 BOM, or else GBK is used if the content is not valid UTF-8
+*/
+func NewExcelFromCSV(reader io.Reader, options ...Option) (e *Excel, err error) {
+	e = newExcel()
+	for _, option := range options {
+		option(e)
+	}
+	if e.headerRow == 0 {
+		e.headerRow = _defaultCSVHeaderRow
+	}
+
+	records, err := readCSV(reader, e.csvComma)
+	if err != nil {
+		return nil, fmt.Errorf("read csv error:(%+v)", err)
+	}
+	collapseRepeatedHeaders(records[:min(e.headerRow, len(records))])
+
+	e.file = excelize.NewFile()
+	sheetName := fmt.Sprintf("%s%d", e.sheetPrefix, 1)
+	if e.sheetPrefix != _defaultSheetPrefix {
+		if _, err = e.file.NewSheet(sheetName); err != nil {
+			return nil, err
+		}
+		// delete default sheet
+		if err = e.file.DeleteSheet("Sheet1"); err != nil {
+			return nil, err
+		}
+	}
+	e.activeSheetNames = []string{sheetName}
+
+	for i, record := range records {
+		var axis string
+		if axis, err = excelize.CoordinatesToCellName(1, i+1); err != nil {
+			return nil, err
+		}
+		values := make([]interface{}, len(record))
+		for j := range record {
+			values[j] = record[j]
+		}
+		if err = e.file.SetSheetRow(sheetName, axis, &values); err != nil {
+			return nil, errors.Wrap(err, "e.file.SetSheetRow")
+		}
+	}
+
+	if err = e.doAfterCreateFile(nil, nil); err != nil {
+		return nil, err
+	}
+	return
+}
+
+/*
+*
+readCSV read all records of csv, the UTF-8 BOM is skipped and GBK content is decoded to UTF-8
+*/
+func readCSV(reader io.Reader, comma rune) ([][]string, error) {
+	br := bufio.NewReaderSize(reader, _csvSniffSize)
+	head, err := br.Peek(_csvSniffSize)
+	if err != nil && err != io.EOF && err != bufio.ErrBufferFull {
+		return nil, err
+	}
+
+	var r io.Reader = br
+	switch {
+	case bytes.HasPrefix(head, _utf8BOM):
+		_, _ = br.Discard(len(_utf8BOM))
+	case !validUTF8Prefix(head):
+		r = transform.NewReader(br, simplifiedchinese.GBK.NewDecoder())
+	}
+
+	csvReader := csv.NewReader(r)
+	if comma != 0 {
+		csvReader.Comma = comma
+	}
+	csvReader.FieldsPerRecord = -1
+	csvReader.LazyQuotes = true
+	return csvReader.ReadAll()
+}
+
+/*
+*
+validUTF8Prefix report whether b is valid UTF-8, a rune cut off at the end of b is ignored
+*/
+func validUTF8Prefix(b []byte) bool {
+	for i := 0; i < len(b); {
+		r, size := utf8.DecodeRune(b[i:])
+		if r == utf8.RuneError && size <= 1 {
+			return len(b)-i < utf8.UTFMax && !utf8.FullRune(b[i:])
+		}
+		i += size
+	}
+	return true
+}
+
+/*
+*
+collapseRepeatedHeaders blank the header cells which repeat the left cell under the same parent,
+so that the header spans them like a merge cell
+*/
+func collapseRepeatedHeaders(headerRows [][]string) {
+	for i, row := range headerRows {
+		for j := len(row) - 1; j > 0; j-- {
+			if row[j] == "" || row[j] != row[j-1] {
+				continue
+			}
+
+			// the parent header must span both cells
+			sameParent := true
+			for k := 0; k < i; k++ {
+				if j < len(headerRows[k]) && headerRows[k][j] != "" {
+					sameParent = false
+					break
+				}
+			}
+			if sameParent {
+				row[j] = ""
+			}
+		}
+	}
+}
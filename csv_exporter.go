@@ -0,0 +1,259 @@
+package excel
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"golang.org/x/text/encoding/simplifiedchinese"
+	"golang.org/x/text/transform"
+)
+
+const (
+	_defaultCSVHeaderSeparator = "-"
+)
+
+type CSVEncoding int
+
+const (
+	CSVEncodingUTF8 CSVEncoding = iota
+	// CSVEncodingUTF8BOM utf-8 with BOM, so that excel opens the csv correctly
+	CSVEncodingUTF8BOM
+	CSVEncodingGBK
+)
+
+type csvOptions struct {
+	comma              rune
+	encoding           CSVEncoding
+	hierarchicalHeader bool
+	headerSeparator    string
+}
+
+type CSVOption func(*csvOptions)
+
+/*
+*
+CSVDelimiter set the field delimiter of csv, ex: '\t' for tsv, default is ','
+*/
+func CSVDelimiter(comma rune) CSVOption {
+	return func(o *csvOptions) {
+		o.comma = comma
+	}
+}
+
+/*
+*
+WithCSVEncoding set the encoding of csv, default is CSVEncodingUTF8
+*/
+func WithCSVEncoding(encoding CSVEncoding) CSVOption {
+	return func(o *csvOptions) {
+		o.encoding = encoding
+	}
+}
+
+/*
+*
+CSVHierarchicalHeader write the header in multi rows like the xlsx exporter, a header is written at its first col only,
+otherwise the header is flattened into one row, ex: 基础信息-OA合同编号
+*/
+func CSVHierarchicalHeader(hierarchical bool) CSVOption {
+	return func(o *csvOptions) {
+		o.hierarchicalHeader = hierarchical
+	}
+}
+
+/*
+*
+CSVHeaderSeparator set the separator of the flattened header, default is "-"
+*/
+func CSVHeaderSeparator(separator string) CSVOption {
+	return func(o *csvOptions) {
+		o.headerSeparator = separator
+	}
+}
+
+/*
+*
+CSVWriter write ex tagged structs to csv row by row
+*/
+type CSVWriter struct {
+	w       *csv.Writer
+	encoder io.WriteCloser
+	typ     reflect.Type
+	fields  []*exField
+}
+
+/*
+*
+NewCSVWriter create a CSVWriter and write the header parsed from proto, proto must be a struct pointer
+Note: Close must be called to flush the rows
+*/
+func NewCSVWriter(w io.Writer, proto interface{}, opts ...CSVOption) (cw *CSVWriter, err error) {
+	o := &csvOptions{headerSeparator: _defaultCSVHeaderSeparator}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	cw = new(CSVWriter)
+	switch o.encoding {
+	case CSVEncodingUTF8BOM:
+		if _, err = w.Write(_utf8BOM); err != nil {
+			return nil, err
+		}
+	case CSVEncodingGBK:
+		cw.encoder = transform.NewWriter(w, simplifiedchinese.GBK.NewEncoder())
+		w = cw.encoder
+	}
+	cw.w = csv.NewWriter(w)
+	if o.comma != 0 {
+		cw.w.Comma = o.comma
+	}
+
+	h, err := parseHeader(proto)
+	if err != nil {
+		return nil, errors.Wrap(err, "parseHeader")
+	}
+	cw.typ = reflect.Indirect(reflect.ValueOf(proto).Elem()).Type()
+	// the cols are in the order of header leaves, the same as the xlsx exporter
+	cw.fields = exportFields(cw.typ)
+
+	if err = cw.writeHeader(h.getLeafPaths(nil), o); err != nil {
+		return nil, err
+	}
+	return
+}
+
+/*
+*
+getLeafPaths return the paths of the leaf headers in order
+*/
+func (h *header) getLeafPaths(prefix []string) (paths [][]string) {
+	path := prefix
+	if !h.isDummy {
+		path = append(append([]string{}, prefix...), h.title)
+	}
+	if len(h.children) == 0 {
+		if len(path) == 0 {
+			return nil
+		}
+		return [][]string{path}
+	}
+	for _, child := range h.children {
+		paths = append(paths, child.getLeafPaths(path)...)
+	}
+	return
+}
+
+func (cw *CSVWriter) writeHeader(leafPaths [][]string, o *csvOptions) error {
+	if len(leafPaths) == 0 {
+		return nil
+	}
+	if !o.hierarchicalHeader {
+		record := make([]string, len(leafPaths))
+		for i, path := range leafPaths {
+			record[i] = strings.Join(path, o.headerSeparator)
+		}
+		return cw.w.Write(record)
+	}
+
+	for depth := range leafPaths[0] {
+		record := make([]string, len(leafPaths))
+		for i, path := range leafPaths {
+			// a header spans the cols which have the same path prefix
+			if i == 0 || !reflect.DeepEqual(path[:depth+1], leafPaths[i-1][:depth+1]) {
+				record[i] = path[depth]
+			}
+		}
+		if err := cw.w.Write(record); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+/*
+*
+Write write a row, row must be a pointer of the proto type
+*/
+func (cw *CSVWriter) Write(row interface{}) error {
+	v := reflect.Indirect(reflect.ValueOf(row).Elem())
+	if v.Type() != cw.typ {
+		return errors.Errorf("row type %s is not %s", v.Type(), cw.typ)
+	}
+
+	record := make([]string, len(cw.fields))
+	for i, field := range cw.fields {
+		if field != nil {
+			record[i] = formatCSVValue(exportValue(v.Field(field.index)))
+		}
+	}
+	return cw.w.Write(record)
+}
+
+/*
+*
+Close flush the buffered rows, the underlying writer is not closed
+*/
+func (cw *CSVWriter) Close() error {
+	cw.w.Flush()
+	if err := cw.w.Error(); err != nil {
+		return err
+	}
+	if cw.encoder != nil {
+		return cw.encoder.Close()
+	}
+	return nil
+}
+
+/*
+*
+formatCSVValue format an export value into the text which the fields can translate back
+*/
+func formatCSVValue(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case time.Time:
+		if v.IsZero() {
+			return ""
+		}
+		return v.Format(_dateLayout)
+	case bool:
+		if v {
+			return "是"
+		}
+		return "否"
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case float32:
+		return strconv.FormatFloat(float64(v), 'f', -1, 32)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+/*
+*
+ExportCSV write rows of ex tagged structs to w as csv, the header is parsed from rows[0]
+*/
+func ExportCSV(w io.Writer, rows []interface{}, opts ...CSVOption) (err error) {
+	if len(rows) == 0 {
+		return
+	}
+
+	cw, err := NewCSVWriter(w, rows[0], opts...)
+	if err != nil {
+		return errors.Wrap(err, "NewCSVWriter")
+	}
+	for i, row := range rows {
+		if err = cw.Write(row); err != nil {
+			return errors.Wrapf(err, "cw.Write row:(%d)", i)
+		}
+	}
+	return cw.Close()
+}
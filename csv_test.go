@@ -0,0 +1,40 @@
+package excel
+
+import (
+	"strings"
+	"testing"
+)
+
+type csvContract struct {
+	Number StringField `ex:"基础信息|OA合同编号"`
+	Name   StringField `ex:"基础信息|合同名称"`
+	Status StringField `ex:"OA信息|审批状态"`
+	Amount FloatField  `ex:"OA信息|金额"`
+}
+
+func TestNewExcelFromCSVTrailingParent(t *testing.T) {
+	// the repeated parent headers span their cols, the last parent OA信息 spans two cols too
+	data := "基础信息,基础信息,OA信息,OA信息\n" +
+		"OA合同编号,合同名称,审批状态,金额\n" +
+		"HT001,采购合同,已通过,1024.5\n"
+	e, err := NewExcelFromCSV(strings.NewReader(data), HeaderRow(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rows, err := e.GetRowsWithoutHeader()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 1 {
+		t.Fatalf("expect 1 data row, got %q", rows)
+	}
+	resp := &csvContract{}
+	if _, err = e.ScanExRow(rows[0], resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.Number.GetValue() != "HT001" || resp.Name.GetValue() != "采购合同" ||
+		resp.Status.GetValue() != "已通过" || resp.Amount.GetValue() != 1024.5 {
+		t.Fatalf("unexpected scanned row %+v", resp)
+	}
+}
@@ -0,0 +1,123 @@
+package excel
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/xuri/excelize/v2"
+)
+
+/*
+*
+headerDetect detect the header block in the first maxRows rows by the expected ex paths
+*/
+type headerDetect struct {
+	maxRows int
+	// expected ex paths, key is the joined path
+	paths map[string]bool
+	// distinct path depths
+	depths []int
+}
+
+func newHeaderDetect(maxRows int, resps ...interface{}) *headerDetect {
+	detect := &headerDetect{maxRows: maxRows, paths: make(map[string]bool)}
+	depths := make(map[int]bool)
+	for _, resp := range resps {
+		typ := reflect.TypeOf(resp)
+		for typ.Kind() == reflect.Ptr {
+			typ = typ.Elem()
+		}
+		for i := 0; i < typ.NumField(); i++ {
+			paths, _ := fieldPaths(typ.Field(i))
+			for _, path := range paths {
+				detect.paths[strings.Join(path, "|")] = true
+
+				if depth := len(path); !depths[depth] {
+					depths[depth] = true
+					detect.depths = append(detect.depths, depth)
+				}
+			}
+		}
+	}
+	return detect
+}
+
+/*
+*
+score return the count of expected paths in the header rows
+*/
+func (d *headerDetect) score(headerRows [][]string, lastColIndex int) int {
+	matched := make(map[string]bool)
+	// values of the header cell which spans the current col in every header row
+	path := make([]string, len(headerRows))
+	for col := 0; col < lastColIndex; col++ {
+		for i, row := range headerRows {
+			if col < len(row) && row[col] != "" {
+				path[i] = row[col]
+				// a new parent header begins, the children must begin at the same col
+				for j := i + 1; j < len(path); j++ {
+					path[j] = ""
+				}
+			}
+		}
+
+		key := strings.Join(path, "|")
+		if d.paths[key] {
+			matched[key] = true
+		}
+	}
+	return len(matched)
+}
+
+/*
+*
+detectHeaders find the header rows which match most expected paths, the rows above the header
+such as titles, notes and blank rows are skipped
+*/
+func (e *Excel) detectHeaders(sheet string, detect *headerDetect) (headers []excelize.MergeCell, err error) {
+	rows, err := e.getHeaderRows(sheet, detect.maxRows)
+	if err != nil {
+		err = errors.Wrap(err, "e.getHeaderRows")
+		return
+	}
+	lastColIndex, err := e.getSheetLastColIndex(sheet)
+	if err != nil {
+		err = errors.Wrap(err, "e.getSheetLastColIndex")
+		return
+	}
+
+	var bestScore, bestStart, bestDepth int
+	for _, depth := range detect.depths {
+		for start := 0; start+depth <= len(rows); start++ {
+			if score := detect.score(rows[start:start+depth], lastColIndex); score > bestScore {
+				bestScore, bestStart, bestDepth = score, start, depth
+			}
+		}
+	}
+	if bestScore == 0 {
+		err = errors.Errorf("no header is detected in the first %d rows of sheet %s", detect.maxRows, sheet)
+		return
+	}
+
+	return e.headersFromRows(rows[bestStart:bestStart+bestDepth], bestStart)
+}
+
+/*
+*
+GetHeaderRowIndexPos return the row start index and row end index of the header block
+*/
+func (root *Importer) GetHeaderRowIndexPos() (rowIndexStart, rowIndexEnd int) {
+	if len(root.childImporters) == 0 {
+		return
+	}
+	return root.childImporters[0].rowIndexStart, root.getRowsBeginIndex()
+}
+
+/*
+*
+GetHeaderRowIndexPos return the row start index and row end index of the header block in the active sheet
+*/
+func (e *Excel) GetHeaderRowIndexPos() (rowIndexStart, rowIndexEnd int) {
+	return e.importers[_defaultSheetIndex].GetHeaderRowIndexPos()
+}
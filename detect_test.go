@@ -0,0 +1,91 @@
+package excel
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/xuri/excelize/v2"
+)
+
+type trailingParentRow struct {
+	Number StringField `ex:"合同|编号"`
+	Name   StringField `ex:"合同|名称"`
+	Signer StringField `ex:"签约方|甲方"`
+	Ratio  FloatField  `ex:"签约方|比例"`
+}
+
+/*
+*
+newRowsExcel build a workbook whose rows are written as they are, without merge cells
+*/
+func newRowsExcel(tb testing.TB, rows [][]interface{}, options ...Option) *Excel {
+	tb.Helper()
+	f := excelize.NewFile()
+	sheet := f.GetSheetName(0)
+	for i, row := range rows {
+		axis, _ := excelize.CoordinatesToCellName(1, i+1)
+		_ = f.SetSheetRow(sheet, axis, &row)
+	}
+
+	var buf bytes.Buffer
+	if err := f.Write(&buf); err != nil {
+		tb.Fatal(err)
+	}
+	e, err := NewExcelFromReader(&buf, options...)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	return e
+}
+
+func TestHeadersTrailingParent(t *testing.T) {
+	// the last parent 签约方 spans two cols, but its row is trimmed after its first cell
+	header := [][]interface{}{
+		{"合同", "", "签约方"},
+		{"编号", "名称", "甲方", "比例"},
+	}
+	data := []interface{}{"HT-001", "采购合同", "甲公司", "0.6"}
+
+	cases := map[string]struct {
+		rows             [][]interface{}
+		option           Option
+		headerRowIndexes [2]int
+	}{
+		"HeaderRow": {
+			rows:             append(header, data),
+			option:           HeaderRow(2),
+			headerRowIndexes: [2]int{1, 2},
+		},
+		"DetectHeader": {
+			rows:             append([][]interface{}{{"合同台账"}, {}}, append(header, data)...),
+			option:           DetectHeader(10, &trailingParentRow{}),
+			headerRowIndexes: [2]int{3, 4},
+		},
+	}
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			e := newRowsExcel(t, c.rows, c.option)
+			if start, end := e.GetHeaderRowIndexPos(); start != c.headerRowIndexes[0] || end != c.headerRowIndexes[1] {
+				t.Fatalf("expect header rows %v, got (%d,%d)", c.headerRowIndexes, start, end)
+			}
+			if start, end := e.SubImporter("签约方").GetColIndexPos(); start != 3 || end != 4 {
+				t.Fatalf("expect 签约方 spans cols (3,4), got (%d,%d)", start, end)
+			}
+
+			rows, err := e.GetRowsWithoutHeader()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(rows) != 1 {
+				t.Fatalf("expect 1 data row, got %q", rows)
+			}
+			resp := &trailingParentRow{}
+			if _, err = e.ScanExRow(rows[0], resp); err != nil {
+				t.Fatal(err)
+			}
+			if resp.Number.GetValue() != "HT-001" || resp.Signer.GetValue() != "甲公司" || resp.Ratio.GetValue() != 0.6 {
+				t.Fatalf("unexpected scanned row %+v", resp)
+			}
+		})
+	}
+}
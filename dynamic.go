@@ -0,0 +1,132 @@
+package excel
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+/*
+*
+ColumnSpec describe a column scanned by ScanRowToMap, it works like a struct field with ex tag
+*/
+type ColumnSpec struct {
+	// Path is the ex path of the column, ex: "电子签合同信息|基础信息|OA合同编号"
+	Path string
+	// Type translates the cell like the field type of struct, ex: IntField{}, default is StringField{}
+	Type ImportField
+	// Required makes the scan fail if the header is missing or the cell is empty
+	Required bool
+}
+
+/*
+*
+columnPlanKey the key of the leaf index of a column path in importer scan plans
+*/
+type columnPlanKey struct {
+	path string
+}
+
+/*
+*
+getColumnLeafIndex return the index of the leaf node which the column path matches, -1 if not found,
+the path matches the headers like the ex tag of a struct field, the result is cached in importer
+*/
+func (root *Importer) getColumnLeafIndex(path string) int {
+	key := columnPlanKey{path: path}
+	if leafIndex, ok := root.scanPlans.Load(key); ok {
+		return leafIndex.(int)
+	}
+
+	field := reflect.StructField{Name: path, Tag: reflect.StructTag("ex:" + strconv.Quote(path))}
+	leafIndex, _ := root.findLeafNode(field, false)
+	actual, _ := root.scanPlans.LoadOrStore(key, leafIndex)
+	return actual.(int)
+}
+
+/*
+*
+ScanRowToMap scan a excel row to map keyed by the joined ex path, the values are the values of the translated ImportField,
+ex: {"电子签合同信息|基础信息|OA合同编号": 1}
+if specs is empty, every leaf header is scanned as string; otherwise only the columns of specs are scanned,
+the columns whose header is missing are absent from the result.
+the errors of all failed cells are aggregated into ScanErrors
+*/
+func (root *Importer) ScanRowToMap(row []string, specs ...ColumnSpec) (res map[string]interface{}, err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			err = fmt.Errorf("ScanRowToMap: internal error: %v", p)
+		}
+	}()
+	if len(root.leafNodes) == 0 {
+		root.leafNodes = root.getLeafNodes()
+	}
+	leafNodesLength := len(root.leafNodes)
+	rowLength := len(row)
+	if leafNodesLength < rowLength {
+		row = row[rowLength-leafNodesLength:]
+	}
+
+	if rowLength < leafNodesLength {
+		for i := 0; i < leafNodesLength-rowLength; i++ {
+			row = append(row, "")
+		}
+	}
+
+	if len(specs) == 0 {
+		res = make(map[string]interface{}, leafNodesLength)
+		for j, leafNode := range root.leafNodes {
+			res[strings.Join(leafNode.path, "|")] = row[j]
+		}
+		return
+	}
+
+	res = make(map[string]interface{}, len(specs))
+	var scanErrs ScanErrors
+	for _, spec := range specs {
+		j := root.getColumnLeafIndex(spec.Path)
+		if j == -1 {
+			if spec.Required {
+				return nil, errors.Errorf("header %s is missing", strings.ReplaceAll(spec.Path, "|", "-"))
+			}
+			continue
+		}
+
+		leafNode := root.leafNodes[j]
+		if spec.Required && strings.TrimSpace(row[j]) == "" {
+			scanErrs = append(scanErrs, &ScanError{ColIndex: leafNode.colIndexStart, Path: leafNode.path, Err: errors.New("the cell is required")})
+			continue
+		}
+
+		typ := spec.Type
+		if typ == nil {
+			typ = StringField{}
+		}
+		setValue, err := typ.Translate(row[j], leafNode.colIndexStart)
+		if err != nil {
+			scanErr := &ScanError{ColIndex: leafNode.colIndexStart, Path: leafNode.path, Err: err}
+			if root.withHumanErrorMsg {
+				scanErr.Err = errors.Errorf("%s 单元格填写错误，请检查", strings.Join(leafNode.path, "-"))
+			}
+			scanErrs = append(scanErrs, scanErr)
+			continue
+		}
+		res[spec.Path] = setValue.(ImportField).GetValue()
+	}
+
+	if len(scanErrs) != 0 {
+		return nil, scanErrs
+	}
+	return
+}
+
+/*
+*
+ScanRowToMap scan a excel row of the active sheet to map, see Importer.ScanRowToMap
+*/
+func (e *Excel) ScanRowToMap(row []string, specs ...ColumnSpec) (map[string]interface{}, error) {
+	return e.importers[_defaultSheetIndex].ScanRowToMap(row, specs...)
+}
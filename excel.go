@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"io"
 	"sync"
+	"time"
 
 	"github.com/pkg/errors"
 	"github.com/xuri/excelize/v2"
@@ -20,10 +21,30 @@ type Excel struct {
 	importers           []*Importer
 	activeSheetNames    []string
 	asyncScanWorkerNums int
+	asyncScanOrdered    bool
+	asyncScanBufferSize int
 	humanErrorMsg       bool
+	fillMergedBody      bool
+	headerDetect        *headerDetect
+	normalizeHeader     bool
+	headerSimilarity    float64
+	headerCheck         *headerCheck
+	progressFn          func(p Progress)
+	progressInterval    time.Duration
+	progress            *progressReporter
+	csvComma            rune
+	encryptPassword     string
+	sheetProtect        *excelize.SheetProtectionOptions
 
 	// style
-	fieldStyleId int
+	fieldStyleId       int
+	style              *exportStyle
+	headerStyleIds     map[int]int
+	conditionalFormats []conditionalFormat
+	summary            *summary
+	groupBy            *groupBy
+	styleMu            sync.Mutex
+	styles             styleCache
 }
 
 func (e *Excel) doAfterCreateFile(rows []interface{}, initData initData) error {
@@ -52,6 +73,16 @@ func (e *Excel) doAfterCreateFile(rows []interface{}, initData initData) error {
 	}
 	e.file.SetActiveSheet(sheetIndex)
 
+	if e.progressFn != nil {
+		e.progress = &progressReporter{fn: e.progressFn, interval: e.progressInterval}
+		if e.progress.interval <= 0 {
+			e.progress.interval = _defaultProgressInterval
+		}
+		if len(rows) != 0 {
+			e.initProgress(rows)
+		}
+	}
+
 	// set style
 	if err := e.initStyle(); err != nil {
 		return fmt.Errorf("init excel style error:(%+v)", err)
@@ -68,6 +99,14 @@ func (e *Excel) doAfterCreateFile(rows []interface{}, initData initData) error {
 		return fmt.Errorf("init excel importers error:(%+v)", err)
 	}
 
+	if err := e.checkHeaders(); err != nil {
+		return err
+	}
+
+	if len(rows) == 0 {
+		e.initProgress(nil)
+	}
+
 	return nil
 }
 
@@ -78,7 +117,7 @@ func (e *Excel) initStyle() (err error) {
 		ShrinkToFit: true,
 	}
 
-	e.fieldStyleId, err = e.file.NewStyle(&excelize.Style{
+	e.fieldStyleId, err = e.newStyle(&excelize.Style{
 		Alignment: alignment,
 	})
 	if err != nil {
@@ -91,30 +130,8 @@ func (e *Excel) initStyle() (err error) {
 func (e *Excel) initImporters() (err error) {
 	e.once.Do(func() {
 		for _, sheetName := range e.activeSheetNames {
-			root := new(Importer)
-			root.value = sheetName
-			root.colIndexStart = _colIndexStart
-			if root.colIndexEnd, err = e.getSheetLastColIndex(sheetName); err != nil {
-				return
-			}
-
-			// get sheet headers in merge cells format
-			mergeCells, err := e.getHeaders(sheetName)
-			if err != nil {
-				return
-			}
-
-			/*
-				set async scan worker nums
-			*/
-			asyncScanWorkerNums := e.asyncScanWorkerNums
-			if asyncScanWorkerNums == 0 {
-				asyncScanWorkerNums = _defaultAsyncScanExRowsGoroutineNums
-			}
-			root.asyncScanWorkerNums = e.asyncScanWorkerNums
-			root.withHumanErrorMsg = e.humanErrorMsg
-
-			if root.childImporters, err = buildImporterTree(root, mergeCells); err != nil {
+			var root *Importer
+			if root, err = e.buildSheetImporter(sheetName, e.headerRow, e.headerDetect); err != nil {
 				return
 			}
 			e.importers = append(e.importers, root)
@@ -124,19 +141,72 @@ func (e *Excel) initImporters() (err error) {
 	return err
 }
 
-func (e *Excel) getHeaders(sheet string) (headers []excelize.MergeCell, err error) {
-	if e.headerRow == 0 {
-		headers, err = e.file.GetMergeCells(sheet)
+/*
+*
+buildSheetImporter build the importer tree of sheet, headerRow is the same as option HeaderRow,
+the header is detected by the expected paths if detect is not nil
+*/
+func (e *Excel) buildSheetImporter(sheetName string, headerRow int, detect *headerDetect) (root *Importer, err error) {
+	root = new(Importer)
+	root.value = sheetName
+	root.colIndexStart = _colIndexStart
+	if root.colIndexEnd, err = e.getSheetLastColIndex(sheetName); err != nil {
+		return nil, err
+	}
+
+	// get sheet headers in merge cells format
+	mergeCells, err := e.getHeaders(sheetName, headerRow, detect)
+	if err != nil {
+		return nil, err
+	}
+
+	/*
+		set async scan worker nums
+	*/
+	asyncScanWorkerNums := e.asyncScanWorkerNums
+	if asyncScanWorkerNums == 0 {
+		asyncScanWorkerNums = _defaultAsyncScanExRowsGoroutineNums
+	}
+	root.asyncScanWorkerNums = asyncScanWorkerNums
+	root.asyncScanOrdered = e.asyncScanOrdered
+	root.asyncScanBufferSize = e.asyncScanBufferSize
+	root.progress = e.progress
+	root.withHumanErrorMsg = e.humanErrorMsg
+	root.normalizeHeader = e.normalizeHeader
+	root.headerSimilarity = e.headerSimilarity
+
+	if err = sortMergeCells(mergeCells); err != nil {
+		return nil, errors.Wrap(err, "sortMergeCells")
+	}
+	if root.childImporters, err = buildImporterTree(root, mergeCells); err != nil {
+		return nil, err
+	}
+	return root, nil
+}
+
+func (e *Excel) getHeaders(sheet string, headerRow int, detect *headerDetect) (headers []excelize.MergeCell, err error) {
+	if detect != nil {
+		headers, err = e.detectHeaders(sheet, detect)
+	} else if headerRow == 0 {
+		if headers, err = e.file.GetMergeCells(sheet); err != nil || !e.fillMergedBody {
+			return
+		}
+
+		// body merge cells are not headers
+		var lastColIndex int
+		if lastColIndex, err = e.getSheetLastColIndex(sheet); err != nil {
+			return
+		}
+		headers, _, err = splitBodyMergeCells(headers, lastColIndex)
 	} else {
-		headers, err = e.getHeadersFromRow(sheet)
+		headers, err = e.getHeadersFromRow(sheet, headerRow)
 	}
-	fmt.Println(headers, len(headers))
 
 	return
 }
 
-func (e *Excel) getHeadersFromRow(sheet string) (headers []excelize.MergeCell, err error) {
-	headerRows, err := e.getHeaderRows(sheet)
+func (e *Excel) getHeadersFromRow(sheet string, headerRow int) (headers []excelize.MergeCell, err error) {
+	headerRows, err := e.getHeaderRows(sheet, headerRow)
 	if err != nil {
 		err = errors.Wrap(err, "e.getHeaderRows")
 		return
@@ -145,6 +215,21 @@ func (e *Excel) getHeadersFromRow(sheet string) (headers []excelize.MergeCell, e
 		return
 	}
 
+	return e.headersFromRows(headerRows, 0)
+}
+
+/*
+*
+headersFromRows build merge cell headers from header rows, rowOffset is the row index before the first header row,
+a header spans from its cell to the cell before the next not empty cell in the same row,
+the last header of a row spans to the end of the block, because the empty cells at the tail of a parent row are trimmed
+*/
+func (e *Excel) headersFromRows(headerRows [][]string, rowOffset int) (headers []excelize.MergeCell, err error) {
+	var width int
+	for _, row := range headerRows {
+		width = max(width, len(row))
+	}
+
 	for i, row := range headerRows {
 		if len(row) == 0 || row[0] == "" {
 			continue
@@ -169,11 +254,11 @@ func (e *Excel) getHeadersFromRow(sheet string) (headers []excelize.MergeCell, e
 				start:  j,
 			})
 		}
-		headerIndices[len(headerIndices)-1].end = len(row) - 1
+		headerIndices[len(headerIndices)-1].end = width - 1
 
 		for _, headerIndex := range headerIndices {
 			var header excelize.MergeCell
-			header, err = e.getMergeCell(headerIndex.start+1, headerIndex.end+1, i+1, headerIndex.header)
+			header, err = e.getMergeCell(headerIndex.start+1, headerIndex.end+1, rowOffset+i+1, headerIndex.header)
 			if err != nil {
 				err = errors.Wrap(err, "e.getMergeCell")
 				return
@@ -185,14 +270,13 @@ func (e *Excel) getHeadersFromRow(sheet string) (headers []excelize.MergeCell, e
 	return
 }
 
-func (e *Excel) getHeaderRows(sheet string) ([][]string, error) {
+func (e *Excel) getHeaderRows(sheet string, headerRow int) ([][]string, error) {
 	rows, err := e.file.Rows(sheet)
 	if err != nil {
 		return nil, err
 	}
 	results := make([][]string, 0, 64)
 
-	headerRow := e.headerRow
 	for rows.Next() && headerRow > 0 {
 		row, err := rows.Columns()
 		if err != nil {
@@ -236,7 +320,7 @@ func NewExcelFromFile(file string, options ...Option) (e *Excel, err error) {
 	for _, option := range options {
 		option(e)
 	}
-	if e.file, err = excelize.OpenFile(file, excelize.Options{Password: e.password}); err != nil {
+	if e.file, err = openExcelFile(file, e.password); err != nil {
 		return nil, fmt.Errorf("open excel file error, file path:(%s), error:(%+v)", file, err)
 	}
 
@@ -251,7 +335,7 @@ func NewExcelFromReader(reader io.Reader, options ...Option) (e *Excel, err erro
 	for _, option := range options {
 		option(e)
 	}
-	if e.file, err = excelize.OpenReader(reader, excelize.Options{Password: e.password}); err != nil {
+	if e.file, err = openExcelReader(reader, e.password); err != nil {
 		return nil, fmt.Errorf("excel file from reader error, error:(%+v)", err)
 	}
 
@@ -344,7 +428,7 @@ func (e *Excel) GetSheetRowsWithoutHeader(sheet string) ([][]string, error) {
 	rowBeginIndex := e.importers[_defaultSheetIndex].getRowsBeginIndex()
 
 	var res [][]string
-	rows, err := e.file.GetRows(sheet)
+	rows, err := e.getSheetDataRows(sheet, rowBeginIndex)
 	if err != nil {
 		return nil, err
 	}
@@ -421,40 +505,26 @@ func (e *Excel) SetSheetCellValue(sheet, axis string, value interface{}) error {
 	return e.file.SetCellValue(sheet, axis, value)
 }
 
+/*
+*
+SetCellColor fill the cell with color, the other parts of the cell style are kept
+*/
 func (e *Excel) SetCellColor(axis string, color string) error {
-	excelStyle := &excelize.Style{
-		Fill: excelize.Fill{
-			Type:    "pattern",
-			Pattern: 1,
-			Color:   []string{color},
-		},
-	}
-	style, err := e.file.NewStyle(excelStyle)
-	if err != nil {
-		return err
-	}
-	if err = e.file.SetCellStyle(e.activeSheetNames[_defaultSheetIndex], axis, axis, style); err != nil {
-		return err
-	}
-	return nil
+	return e.SetSheetCellColor(e.activeSheetNames[_defaultSheetIndex], axis, color)
 }
 
+/*
+*
+SetSheetCellColor fill the cell of sheet with color, the other parts of the cell style are kept
+*/
 func (e *Excel) SetSheetCellColor(sheet, axis string, color string) error {
-	excelStyle := &excelize.Style{
-		Fill: excelize.Fill{
+	return e.ApplyStyle(sheet, axis, StylePatch{
+		Fill: &excelize.Fill{
 			Type:    "pattern",
 			Pattern: 1,
 			Color:   []string{color},
 		},
-	}
-	style, err := e.file.NewStyle(excelStyle)
-	if err != nil {
-		return err
-	}
-	if err = e.file.SetCellStyle(sheet, axis, axis, style); err != nil {
-		return err
-	}
-	return nil
+	})
 }
 
 /*
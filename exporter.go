@@ -3,6 +3,7 @@ package excel
 import (
 	"reflect"
 	"strings"
+	"sync"
 
 	"github.com/pkg/errors"
 	"github.com/xuri/excelize/v2"
@@ -26,12 +27,48 @@ func (e *Excel) initFromData(rows []interface{}) (err error) {
 		err = errors.Wrap(err, "e.writeHeader")
 		return
 	}
+	typ := reflect.Indirect(reflect.ValueOf(rows[0]).Elem()).Type()
+	if e.groupBy != nil {
+		if rows, err = e.sortByGroup(typ, rows); err != nil {
+			err = errors.Wrap(err, "e.sortByGroup")
+			return
+		}
+	}
+
 	dataRow := header.getHeight()
-	if err = e.writeData(rows, dataRow); err != nil {
+	lastRows, blocks, err := e.writeData(rows, dataRow)
+	if err != nil {
 		err = errors.Wrap(err, "e.writeData")
 		return
 	}
 
+	for _, sheet := range e.activeSheetNames {
+		if err = e.setColWidths(sheet, typ, rows); err != nil {
+			err = errors.Wrap(err, "e.setColWidths")
+			return
+		}
+	}
+
+	if err = e.setConditionalFormats(typ, blocks); err != nil {
+		err = errors.Wrap(err, "e.setConditionalFormats")
+		return
+	}
+
+	if err = e.writeSummaries(typ, lastRows, dataRow); err != nil {
+		err = errors.Wrap(err, "e.writeSummaries")
+		return
+	}
+
+	if e.sheetProtect != nil {
+		fields := exportFields(typ)
+		for _, sheet := range e.activeSheetNames {
+			if err = e.protectSheet(sheet, dataRow-1, fields); err != nil {
+				err = errors.Wrap(err, "e.protectSheet")
+				return
+			}
+		}
+	}
+
 	return
 }
 
@@ -39,6 +76,11 @@ func (e *Excel) writeHeader(header *header, col, row int) (span int, err error)
 	if header == nil {
 		return
 	}
+	// the top header is at row 1
+	styleId, err := e.getHeaderStyleId(row - 1)
+	if err != nil {
+		return
+	}
 
 	var childrenSpan int
 	for _, child := range header.children {
@@ -76,7 +118,7 @@ func (e *Excel) writeHeader(header *header, col, row int) (span int, err error)
 					return
 				}
 
-				err = e.file.SetCellStyle(sheet, hCell, vCell, e.fieldStyleId)
+				err = e.file.SetCellStyle(sheet, hCell, vCell, styleId)
 				if err != nil {
 					err = errors.Wrap(err, "e.file.SetCellStyle")
 					return
@@ -101,14 +143,23 @@ func (e *Excel) writeHeader(header *header, col, row int) (span int, err error)
 			err = errors.Wrap(err, "e.file.SetCellValue")
 			return
 		}
+
+		// the leaf cells are not merged, they are styled only if a header style option is set
+		if len(header.children) == 0 && styleId != e.fieldStyleId {
+			err = e.file.SetCellStyle(sheet, axis, axis, styleId)
+			if err != nil {
+				err = errors.Wrap(err, "e.file.SetCellStyle")
+				return
+			}
+		}
 	}
 
 	return
 }
 
 type header struct {
-	isDummy  bool // the root is fake node
-	parent   string
+	isDummy  bool   // the root is fake node
+	parent   string // the joined path of parent
 	title    string
 	children []*header
 }
@@ -127,11 +178,8 @@ func parseHeader(row interface{}) (h *header, err error) {
 
 	var paths [][]string
 	v := reflect.ValueOf(row).Elem()
-	for i := 0; i < reflect.Indirect(v).NumField(); i++ {
-		field := reflect.Indirect(v).Type().Field(i)
-		tag := field.Tag.Get("ex")
-		path := strings.Split(tag, "|")
-		paths = append(paths, path)
+	for _, field := range getExFields(reflect.Indirect(v).Type()) {
+		paths = append(paths, field.path)
 	}
 	if len(paths) == 0 {
 		return
@@ -154,17 +202,14 @@ func getHeadersFromPaths(paths [][]string, colIdx, colMax int) (hs []*header) {
 		return
 	}
 
+	// key is the joined path of header, so that the same title under different parents are different headers
 	hMap := make(map[string]*header)
 	for _, path := range paths {
-		title := path[colIdx]
-		var parent string
-		if colIdx > 0 {
-			parent = path[colIdx-1]
-		}
-		h, ok := hMap[title]
+		key := strings.Join(path[:colIdx+1], "|")
+		h, ok := hMap[key]
 		if !ok {
-			h = &header{parent: parent, title: title}
-			hMap[title] = h
+			h = &header{parent: strings.Join(path[:colIdx], "|"), title: path[colIdx]}
+			hMap[key] = h
 
 			// keep the title order
 			hs = append(hs, h)
@@ -181,7 +226,20 @@ func getHeadersFromPaths(paths [][]string, colIdx, colMax int) (hs []*header) {
 	return hs
 }
 
-func (e *Excel) writeData(rows []interface{}, rowStart int) (err error) {
+/*
+*
+rowBlock the excel rows from first to last of continuous data rows, ex: the data rows of a group
+*/
+type rowBlock struct {
+	first, last int
+}
+
+/*
+*
+writeData write the data rows to the sheets from rowStart, lastRows are the last written rows of sheets,
+blocks are the data rows of sheets, the group header and subtotal rows are not in them
+*/
+func (e *Excel) writeData(rows []interface{}, rowStart int) (lastRows []int, blocks [][]rowBlock, err error) {
 	l := len(rows)
 	if l == 0 {
 		return
@@ -192,44 +250,148 @@ func (e *Excel) writeData(rows []interface{}, rowStart int) (err error) {
 		return
 	}
 
-	sheetRowSize := l / e.sheetCount
-	for idx := 0; idx < e.sheetCount; idx++ {
-		var sheetRows []interface{}
-		if idx == e.sheetCount-1 {
-			sheetRows = rows[idx*sheetRowSize:]
-		} else {
-			sheetRows = rows[idx*sheetRowSize : (idx+1)*sheetRowSize]
-		}
+	typ := reflect.Indirect(reflect.ValueOf(rows[0]).Elem()).Type()
+	colStyles, err := e.getColStyles(typ)
+	if err != nil {
+		err = errors.Wrap(err, "e.getColStyles")
+		return
+	}
 
+	for idx, sheetRows := range e.splitSheetRows(rows) {
 		sheetRowStart := rowStart
 		sheet := e.activeSheetNames[idx]
-		for _, row := range sheetRows {
-			v := reflect.ValueOf(row).Elem()
-			for i := 0; i < reflect.Indirect(v).NumField(); i++ {
-				var axis string
-				axis, err = excelize.CoordinatesToCellName(i+1, sheetRowStart)
-				if err != nil {
-					err = errors.Wrap(err, "excelize.CoordinatesToCellName")
-					return
-				}
-
-				var value interface{}
-				field := reflect.Indirect(v).Field(i)
-				if importField, ok := field.Interface().(ImportField); ok {
-					value = importField.GetValue()
-				} else {
-					value = field
-				}
-				err = e.file.SetCellValue(sheet, axis, value)
-				if err != nil {
-					err = errors.Wrap(err, "e.file.SetCellValue")
+		if e.groupBy != nil {
+			var groupBlocks []rowBlock
+			if sheetRowStart, groupBlocks, err = e.writeGroups(sheet, typ, sheetRows, sheetRowStart, colStyles); err != nil {
+				err = errors.Wrap(err, "e.writeGroups")
+				return
+			}
+			blocks = append(blocks, groupBlocks)
+		} else {
+			for i, row := range sheetRows {
+				if err = e.writeDataRow(sheet, row, sheetRowStart, i, colStyles); err != nil {
 					return
 				}
+				sheetRowStart++
 			}
+			blocks = append(blocks, []rowBlock{{first: rowStart, last: sheetRowStart - 1}})
+		}
+		lastRows = append(lastRows, sheetRowStart-1)
+		e.progress.sheetDone(sheet)
+	}
+
+	return
+}
+
+/*
+*
+writeDataRow write a data row at rowIndex, i is the index of the row in its block for the zebra rows
+*/
+func (e *Excel) writeDataRow(sheet string, row interface{}, rowIndex, i int, colStyles []colStyle) (err error) {
+	v := reflect.Indirect(reflect.ValueOf(row).Elem())
+	for j, exField := range exportFields(v.Type()) {
+		var axis string
+		axis, err = excelize.CoordinatesToCellName(j+1, rowIndex)
+		if err != nil {
+			return errors.Wrap(err, "excelize.CoordinatesToCellName")
+		}
 
-			sheetRowStart++
+		err = e.file.SetCellValue(sheet, axis, exportValue(v.Field(exField.index)))
+		if err != nil {
+			return errors.Wrap(err, "e.file.SetCellValue")
+		}
+
+		if colStyles != nil {
+			styleId := colStyles[j].odd
+			if i%2 == 1 {
+				styleId = colStyles[j].even
+			}
+			err = e.file.SetCellStyle(sheet, axis, axis, styleId)
+			if err != nil {
+				return errors.Wrap(err, "e.file.SetCellStyle")
+			}
 		}
 	}
 
+	e.progress.rowWritten(sheet)
 	return
 }
+
+/*
+*
+exportValue return the value of a struct field to export
+*/
+func exportValue(field reflect.Value) interface{} {
+	if importField, ok := field.Interface().(ImportField); ok {
+		return importField.GetValue()
+	}
+	return field.Interface()
+}
+
+/*
+*
+splitSheetRows split the data rows to the sheets evenly, the last sheet takes the rest rows
+*/
+func (e *Excel) splitSheetRows(rows []interface{}) [][]interface{} {
+	res := make([][]interface{}, 0, e.sheetCount)
+	sheetRowSize := len(rows) / e.sheetCount
+	for idx := 0; idx < e.sheetCount; idx++ {
+		if idx == e.sheetCount-1 {
+			res = append(res, rows[idx*sheetRowSize:])
+		} else {
+			res = append(res, rows[idx*sheetRowSize:(idx+1)*sheetRowSize])
+		}
+	}
+	return res
+}
+
+// _exportFieldsCache cache the export fields of struct types, key is reflect.Type, value is []*exField
+var _exportFieldsCache sync.Map
+
+/*
+*
+exportFields return the ex fields of struct type in the order of the leaf headers, the fields are grouped under
+their first seen parent in header, so the exported col index of fields[j] is j+1, the result is cached by type
+*/
+func exportFields(typ reflect.Type) []*exField {
+	if fields, ok := _exportFieldsCache.Load(typ); ok {
+		return fields.([]*exField)
+	}
+
+	exFields := getExFields(typ)
+	paths := make([][]string, 0, len(exFields))
+	fieldsByPath := make(map[string]*exField, len(exFields))
+	for _, field := range exFields {
+		if len(field.path) != len(exFields[0].path) {
+			// the header of the struct is invalid, parseHeader reports it
+			return exFields
+		}
+		paths = append(paths, field.path)
+		if key := strings.Join(field.path, "|"); fieldsByPath[key] == nil {
+			fieldsByPath[key] = field
+		}
+	}
+
+	fields := make([]*exField, 0, len(exFields))
+	if len(paths) != 0 {
+		h := &header{isDummy: true, children: getHeadersFromPaths(paths, 0, len(paths[0]))}
+		for _, path := range h.getLeafPaths(nil) {
+			fields = append(fields, fieldsByPath[strings.Join(path, "|")])
+		}
+	}
+	actual, _ := _exportFieldsCache.LoadOrStore(typ, fields)
+	return actual.([]*exField)
+}
+
+/*
+*
+fieldColIndex return the exported col index of the field of ex path in fields returned by exportFields, -1 if not found
+*/
+func fieldColIndex(fields []*exField, path string) int {
+	for j, field := range fields {
+		if strings.Join(field.path, "|") == path {
+			return j + 1
+		}
+	}
+	return -1
+}
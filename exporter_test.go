@@ -0,0 +1,65 @@
+package excel
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// interleavedRow 合同|金额 is after 签约方|比例, but it's exported under 合同
+type interleavedRow struct {
+	Number StringField `ex:"合同|编号"`
+	Ratio  FloatField  `ex:"签约方|比例"`
+	Amount IntField    `ex:"合同|金额"`
+}
+
+func TestExportInterleavedParents(t *testing.T) {
+	rows := []interface{}{
+		&interleavedRow{Number: NewStringField("HT-1"), Ratio: NewFloatField(0.6), Amount: NewIntField(10)},
+		&interleavedRow{Number: NewStringField("HT-2"), Ratio: NewFloatField(0.4), Amount: NewIntField(20)},
+	}
+	e, err := NewExcelFromData(rows,
+		WithSummary("", Aggregate{Path: "合同|金额", Func: AggregateSum}),
+		WithConditionalFormat("合同|金额", ValueRule(">", "15", &excelize.Style{Font: &excelize.Font{Bold: true}})),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sheet := e.activeSheetNames[0]
+	got, err := e.file.GetRows(sheet)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := [][]string{
+		{"合同", "", "签约方"},
+		{"编号", "金额", "比例"},
+		{"HT-1", "10", "0.6"},
+		{"HT-2", "20", "0.4"},
+		{"合计", ""},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("expect rows %q, got %q", want, got)
+	}
+	if formula, _ := e.file.GetCellFormula(sheet, "B5"); formula != "SUBTOTAL(9,B3:B4)" {
+		t.Fatalf("expect the sum of 金额 at B5, got %q", formula)
+	}
+	formats, err := e.file.GetConditionalFormats(sheet)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := formats["B3:B4"]; !ok || len(formats) != 1 {
+		t.Fatalf("expect the conditional format of 金额 at B3:B4, got %v", formats)
+	}
+
+	// the csv export has the same cols
+	var buf bytes.Buffer
+	if err = ExportCSV(&buf, rows); err != nil {
+		t.Fatal(err)
+	}
+	if want := "合同-编号,合同-金额,签约方-比例\nHT-1,10,0.6\nHT-2,20,0.4\n"; buf.String() != want {
+		t.Fatalf("expect csv %q, got %q", want, buf.String())
+	}
+}
@@ -0,0 +1,196 @@
+package excel
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/xuri/excelize/v2"
+)
+
+const (
+	_defaultSubtotalLabel = "小计"
+	_groupOutlineLevel    = 1
+)
+
+/*
+*
+groupBy the group of option GroupBy
+*/
+type groupBy struct {
+	path          string
+	subtotalLabel string
+}
+
+/*
+*
+sortByGroup sort the rows by the value of group key stably, the numbers and times are compared by value
+*/
+func (e *Excel) sortByGroup(typ reflect.Type, rows []interface{}) ([]interface{}, error) {
+	fields := exportFields(typ)
+	col := fieldColIndex(fields, e.groupBy.path)
+	if col == -1 {
+		return nil, errors.Errorf("no field of ex path %s", e.groupBy.path)
+	}
+	fieldIndex := fields[col-1].index
+
+	keys := make([]interface{}, len(rows))
+	indices := make([]int, len(rows))
+	for i, row := range rows {
+		keys[i] = exportValue(reflect.Indirect(reflect.ValueOf(row).Elem()).Field(fieldIndex))
+		indices[i] = i
+	}
+	less := keyLess(keys)
+	sort.SliceStable(indices, func(a, b int) bool {
+		return less(keys[indices[a]], keys[indices[b]])
+	})
+
+	sorted := make([]interface{}, len(rows))
+	for i, idx := range indices {
+		sorted[i] = rows[idx]
+	}
+	return sorted, nil
+}
+
+/*
+*
+keyLess return the less func of the group keys by their type, the times and numbers are compared by value,
+the others by text. The keys of different types are all compared by text, so that the order is transitive
+*/
+func keyLess(keys []interface{}) func(a, b interface{}) bool {
+	textLess := func(a, b interface{}) bool {
+		return formatCSVValue(a) < formatCSVValue(b)
+	}
+	if len(keys) == 0 {
+		return textLess
+	}
+	typ := reflect.TypeOf(keys[0])
+	for _, key := range keys {
+		if reflect.TypeOf(key) != typ {
+			return textLess
+		}
+	}
+
+	switch {
+	case typ == _timeType:
+		return func(a, b interface{}) bool {
+			return a.(time.Time).Before(b.(time.Time))
+		}
+	case isNumberKind(typ.Kind()):
+		return func(a, b interface{}) bool {
+			return numberValue(a) < numberValue(b)
+		}
+	}
+	return textLess
+}
+
+func isNumberKind(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+func numberValue(v interface{}) float64 {
+	rv := reflect.ValueOf(v)
+	switch {
+	case rv.CanInt():
+		return float64(rv.Int())
+	case rv.CanUint():
+		return float64(rv.Uint())
+	}
+	return rv.Float()
+}
+
+/*
+*
+groupLabelCol return the col of the group label, it's the first col which is not aggregated by option WithSummary,
+or the first col which is not counted if all cols are aggregated, since the count of SUBTOTAL counts the text of label
+*/
+func (e *Excel) groupLabelCol(fields []*exField) int {
+	if e.summary == nil {
+		return 1
+	}
+	aggregated, counted := make(map[int]bool), make(map[int]bool)
+	for _, aggregate := range e.summary.aggregates {
+		col := fieldColIndex(fields, aggregate.Path)
+		aggregated[col] = true
+		counted[col] = counted[col] || aggregate.Func == AggregateCount
+	}
+	for col := 1; col <= len(fields); col++ {
+		if !aggregated[col] {
+			return col
+		}
+	}
+	for col := 1; col <= len(fields); col++ {
+		if !counted[col] {
+			return col
+		}
+	}
+	return 1
+}
+
+/*
+*
+writeGroups write the sorted rows in groups from rowIndex: a group header row merged from the label col to the last col,
+the data rows at outline level 1 so they can be collapsed, and a subtotal row if option WithSummary is set.
+It returns the row index after the last written row and the data rows of groups
+*/
+func (e *Excel) writeGroups(sheet string, typ reflect.Type, rows []interface{}, rowIndex int, colStyles []colStyle) (next int, blocks []rowBlock, err error) {
+	fields := exportFields(typ)
+	field := fields[fieldColIndex(fields, e.groupBy.path)-1]
+	title := field.path[len(field.path)-1]
+	labelCol := e.groupLabelCol(fields)
+
+	for start := 0; start < len(rows); {
+		key := formatCSVValue(exportValue(reflect.Indirect(reflect.ValueOf(rows[start]).Elem()).Field(field.index)))
+		end := start + 1
+		for end < len(rows) && formatCSVValue(exportValue(reflect.Indirect(reflect.ValueOf(rows[end]).Elem()).Field(field.index))) == key {
+			end++
+		}
+
+		// group header
+		labelCell, _ := excelize.CoordinatesToCellName(labelCol, rowIndex)
+		hCell, _ := excelize.CoordinatesToCellName(1, rowIndex)
+		vCell, _ := excelize.CoordinatesToCellName(len(fields), rowIndex)
+		if err = e.file.SetCellValue(sheet, labelCell, fmt.Sprintf("%s：%s", title, key)); err != nil {
+			return 0, nil, errors.Wrap(err, "e.file.SetCellValue")
+		}
+		if labelCol < len(fields) {
+			if err = e.file.MergeCell(sheet, labelCell, vCell); err != nil {
+				return 0, nil, errors.Wrap(err, "e.file.MergeCell")
+			}
+		}
+		if err = e.ApplyStyle(sheet, hCell+":"+vCell, StylePatch{Font: &excelize.Font{Bold: true}}); err != nil {
+			return 0, nil, err
+		}
+		rowIndex++
+
+		block := rowBlock{first: rowIndex}
+		for i, row := range rows[start:end] {
+			if err = e.writeDataRow(sheet, row, rowIndex, i, colStyles); err != nil {
+				return 0, nil, err
+			}
+			if err = e.file.SetRowOutlineLevel(sheet, rowIndex, _groupOutlineLevel); err != nil {
+				return 0, nil, errors.Wrap(err, "e.file.SetRowOutlineLevel")
+			}
+			rowIndex++
+		}
+		block.last = rowIndex - 1
+		blocks = append(blocks, block)
+
+		if e.summary != nil {
+			if err = e.writeSummaryRow(sheet, typ, e.groupBy.subtotalLabel, rowIndex, block.first, block.last); err != nil {
+				return 0, nil, err
+			}
+			rowIndex++
+		}
+		start = end
+	}
+	return rowIndex, blocks, nil
+}
@@ -0,0 +1,113 @@
+package excel
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/xuri/excelize/v2"
+)
+
+type groupRow struct {
+	Number StringField `ex:"合同|编号"`
+	Signer StringField `ex:"合同|签约主体"`
+	Amount IntField    `ex:"合同|金额"`
+}
+
+func newGroupRows(rows ...groupRow) []interface{} {
+	res := make([]interface{}, len(rows))
+	for i := range rows {
+		res[i] = &rows[i]
+	}
+	return res
+}
+
+func TestGroupBySummaryRows(t *testing.T) {
+	rows := newGroupRows(
+		groupRow{NewStringField("HT-1"), NewStringField("A"), NewIntField(10)},
+		groupRow{NewStringField("HT-2"), NewStringField("B"), NewIntField(20)},
+		groupRow{NewStringField("HT-3"), NewStringField("A"), NewIntField(30)},
+	)
+	e, err := NewExcelFromData(rows,
+		GroupBy("合同|签约主体", ""),
+		WithSummary("", Aggregate{Path: "合同|编号", Func: AggregateCount}, Aggregate{Path: "合同|金额", Func: AggregateSum}),
+		WithConditionalFormat("合同|编号", DuplicateRule(&excelize.Style{Font: &excelize.Font{Bold: true}})),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sheet := e.activeSheetNames[0]
+	got, err := e.file.GetRows(sheet)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the group label is written at the first col which isn't aggregated, so the count of 编号 doesn't count it
+	want := [][]string{
+		{"合同"},
+		{"编号", "签约主体", "金额"},
+		{"", "签约主体：A"},
+		{"HT-1", "A", "10"},
+		{"HT-3", "A", "30"},
+		{"", "小计"},
+		{"", "签约主体：B"},
+		{"HT-2", "B", "20"},
+		{"", "小计"},
+		{"", "合计"},
+	}
+	for i := range want {
+		if i >= len(got) || !reflect.DeepEqual(got[i][:min(len(got[i]), len(want[i]))], want[i]) {
+			t.Fatalf("expect rows %q, got %q", want, got)
+		}
+	}
+
+	formulas := map[string]string{
+		"A6":  "SUBTOTAL(3,A4:A5)",
+		"C6":  "SUBTOTAL(9,C4:C5)",
+		"A10": "SUBTOTAL(3,A3:A9)",
+	}
+	for axis, formula := range formulas {
+		if f, _ := e.file.GetCellFormula(sheet, axis); f != formula {
+			t.Fatalf("expect formula %s at %s, got %s", formula, axis, f)
+		}
+	}
+
+	// the conditional format covers the data rows of groups only
+	formats, err := e.file.GetConditionalFormats(sheet)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var refs []string
+	for ref := range formats {
+		refs = append(refs, ref)
+	}
+	if !reflect.DeepEqual(refs, []string{"A4:A5 A8:A8"}) {
+		t.Fatalf("unexpected conditional format ranges %v", refs)
+	}
+}
+
+func TestGroupByKeyOrder(t *testing.T) {
+	// "2" < "10" < "1a" < "2" if numbers and texts are mixed in comparison
+	var rows []interface{}
+	for _, signer := range []string{"2", "10", "1a", "2", "10", "1a"} {
+		rows = append(rows, &groupRow{Signer: NewStringField(signer)})
+	}
+	e := &Excel{groupBy: &groupBy{path: "合同|签约主体"}}
+	sorted, err := e.sortByGroup(reflect.TypeOf(groupRow{}), rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var keys []string
+	for _, row := range sorted {
+		keys = append(keys, row.(*groupRow).Signer.GetValue().(string))
+	}
+	if !sort.StringsAreSorted(keys) {
+		t.Fatalf("the keys of the same group are not adjacent: %v", keys)
+	}
+
+	less := keyLess([]interface{}{10, 2, 1})
+	if !less(2, 10) || less(10, 2) {
+		t.Fatal("the numbers are not compared by value")
+	}
+}
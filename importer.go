@@ -3,6 +3,7 @@ package excel
 import (
 	"fmt"
 	"reflect"
+	"sort"
 	"strings"
 	"sync"
 
@@ -38,14 +39,67 @@ type Importer struct {
 	leafNodes []*Importer
 	// goroutine nums for async scan rows
 	asyncScanWorkerNums int
+	// keep the order of rows for async scan results
+	asyncScanOrdered bool
+	// buffer size of the async scan results channel
+	asyncScanBufferSize int
+	// report the progress of async scan
+	progress *progressReporter
 
 	// with human error message
 	withHumanErrorMsg bool
+	// normalize the header text when the ex path doesn't match
+	normalizeHeader bool
+	// the similarity threshold for fuzzy header matching, 0 means disabled
+	headerSimilarity float64
+
+	// compiled scan plans of struct types, key is scanPlanKey, value is *scanPlan,
+	// and leaf indices of column paths, key is columnPlanKey, value is int
+	scanPlans sync.Map
 }
 
+/*
+*
+AsyncScanExRes the result of a row scanned async
+*/
 type AsyncScanExRes struct {
+	// RowIndex is the row index (begin from 1) of the scanned row in sheet, only set by AsyncScanExRowsContext
+	RowIndex int
+	// Resps are the scanned structs, nil if Err is not nil
 	Resps []interface{}
-	Err   error
+	// ScanErrColIndex is the col index of the first cell which failed to scan
+	ScanErrColIndex int
+	// Err is ScanErrors if some cells failed to scan
+	Err error
+}
+
+/*
+*
+ScanError a cell which failed to scan
+*/
+type ScanError struct {
+	ColIndex int
+	// Path is the header path of the cell
+	Path []string
+	Err  error
+}
+
+func (e *ScanError) Error() string {
+	return fmt.Sprintf("col:(%s) header:(%s) %v", colName(e.ColIndex), strings.Join(e.Path, "-"), e.Err)
+}
+
+/*
+*
+ScanErrors all cells of a row which failed to scan
+*/
+type ScanErrors []*ScanError
+
+func (es ScanErrors) Error() string {
+	msgs := make([]string, 0, len(es))
+	for _, e := range es {
+		msgs = append(msgs, e.Error())
+	}
+	return strings.Join(msgs, "; ")
 }
 
 /*
@@ -65,7 +119,7 @@ func (root *Importer) childrenColBoundaryIndex(mergeCells []excelize.MergeCell)
 	)
 
 	//set to negative to make sure it also work when there is no child.
-	end = -1
+	start, end = -1, -1
 
 	for i, cell := range mergeCells {
 		startAxis, endAxis := cell.GetStartAxis(), cell.GetEndAxis()
@@ -85,6 +139,10 @@ func (root *Importer) childrenColBoundaryIndex(mergeCells []excelize.MergeCell)
 			}
 		}
 	}
+	// no child begins at the first col of root, ex: a merge cell in the body, so the cells are not children
+	if start == -1 || start > end {
+		start, end = 0, -1
+	}
 	return
 }
 
@@ -120,6 +178,30 @@ func buildNodeByCell(mergeCell excelize.MergeCell) (*Importer, error) {
 	return node, nil
 }
 
+/*
+*
+sortMergeCells sort merge cells by the row and then the col of their beginning cells, the children of a node are
+looked up after it in mergeCells, but the merge cells are in the order they were added to the sheet
+*/
+func sortMergeCells(mergeCells []excelize.MergeCell) error {
+	ranges := make(map[string]cellRange, len(mergeCells))
+	for _, mergeCell := range mergeCells {
+		r, err := newCellRange(mergeCell)
+		if err != nil {
+			return err
+		}
+		ranges[mergeCell.GetStartAxis()] = r
+	}
+	sort.SliceStable(mergeCells, func(i, j int) bool {
+		ri, rj := ranges[mergeCells[i].GetStartAxis()], ranges[mergeCells[j].GetStartAxis()]
+		if ri.rowIndexStart != rj.rowIndexStart {
+			return ri.rowIndexStart < rj.rowIndexStart
+		}
+		return ri.colIndexStart < rj.colIndexStart
+	})
+	return nil
+}
+
 /*
 *
 buildImporterTree build a excel tree based by mergeCells
@@ -151,9 +233,7 @@ func buildImporterTree(root *Importer, mergeCells []excelize.MergeCell) ([]*Impo
 			return nil, err
 		}
 
-		// children's async scan worker nums just inherit root
-		node.asyncScanWorkerNums = root.asyncScanWorkerNums
-		node.withHumanErrorMsg = root.withHumanErrorMsg
+		node.inherit(root)
 
 		// children's path
 		node.path = append(node.path, root.path...)
@@ -167,6 +247,20 @@ func buildImporterTree(root *Importer, mergeCells []excelize.MergeCell) ([]*Impo
 	return res, nil
 }
 
+/*
+*
+inherit copy the options of parent, children's async scan worker nums just inherit root
+*/
+func (root *Importer) inherit(parent *Importer) {
+	root.asyncScanWorkerNums = parent.asyncScanWorkerNums
+	root.asyncScanOrdered = parent.asyncScanOrdered
+	root.asyncScanBufferSize = parent.asyncScanBufferSize
+	root.progress = parent.progress
+	root.withHumanErrorMsg = parent.withHumanErrorMsg
+	root.normalizeHeader = parent.normalizeHeader
+	root.headerSimilarity = parent.headerSimilarity
+}
+
 /*
 *
 getRowsBeginIndex return the beginning row index of excel (except of mergeCell headers)
@@ -240,7 +334,7 @@ func (root *Importer) subImporter(path []string) *Importer {
 	if len(path) == 0 {
 		return nil
 	}
-	if len(path) == 1 {
+	if len(path) == 1 && root.path != nil {
 		if path[0] == root.value {
 			return root
 		}
@@ -288,26 +382,20 @@ func (root *Importer) ScanExRow(row []string, resps ...interface{}) (scanErrColI
 	}
 
 	for _, resp := range resps {
-		v := reflect.ValueOf(resp).Elem()
-		for i := 0; i < reflect.Indirect(v).NumField(); i++ {
-			field := reflect.Indirect(v).Type().Field(i)
-			tag := field.Tag.Get("ex")
-			path := strings.Split(tag, "|")
-			for j, leafNode := range root.leafNodes {
-				if reflect.DeepEqual(leafNode.path, path) {
-					var setValue interface{}
-					setValue, err = reflect.Indirect(v).Field(i).Interface().(ImportField).Translate(row[j], leafNode.colIndexStart)
-					if err != nil {
-						if root.withHumanErrorMsg {
-							fmt.Printf("scanExRow scan row:(%+v) error:(%+v)", row, err)
-							return leafNode.colIndexStart, errors.Errorf("%s 单元格填写错误，请检查", strings.Join(leafNode.path, "-"))
-						}
-						return leafNode.colIndexStart, err
-					}
-					reflect.Indirect(v).Field(i).Set(reflect.ValueOf(setValue))
-					break
+		v := reflect.Indirect(reflect.ValueOf(resp).Elem())
+		for _, fieldPlan := range root.getScanPlan(v.Type(), false).fields {
+			i, j := fieldPlan.fieldIndex, fieldPlan.leafIndex
+			leafNode := root.leafNodes[j]
+			var setValue interface{}
+			setValue, err = v.Field(i).Interface().(ImportField).Translate(row[j], leafNode.colIndexStart)
+			if err != nil {
+				if root.withHumanErrorMsg {
+					fmt.Printf("scanExRow scan row:(%+v) error:(%+v)", row, err)
+					return leafNode.colIndexStart, errors.Errorf("%s 单元格填写错误，请检查", strings.Join(leafNode.path, "-"))
 				}
+				return leafNode.colIndexStart, err
 			}
+			v.Field(i).Set(reflect.ValueOf(setValue))
 		}
 	}
 	return
@@ -342,26 +430,20 @@ func (root *Importer) RelativeScanExRow(row []string, resps ...interface{}) (sca
 	}
 
 	for _, resp := range resps {
-		v := reflect.ValueOf(resp).Elem()
-		for i := 0; i < reflect.Indirect(v).NumField(); i++ {
-			field := reflect.Indirect(v).Type().Field(i)
-			tag := field.Tag.Get("ex")
-			path := strings.Split(tag, "|")
-			for j, leafNode := range root.leafNodes {
-				if reflect.DeepEqual(leafNode.path[len(leafNode.path)-len(path):], path) {
-					var setValue interface{}
-					setValue, err = reflect.Indirect(v).Field(i).Interface().(ImportField).Translate(row[j], leafNode.colIndexStart)
-					if err != nil {
-						if root.withHumanErrorMsg {
-							fmt.Printf("scanExRow scan row:(%+v) error:(%+v)", row, err)
-							return leafNode.colIndexStart, errors.Errorf("%s 单元格填写错误，请检查", strings.Join(leafNode.path, "-"))
-						}
-						return leafNode.colIndexStart, err
-					}
-					reflect.Indirect(v).Field(i).Set(reflect.ValueOf(setValue))
-					break
+		v := reflect.Indirect(reflect.ValueOf(resp).Elem())
+		for _, fieldPlan := range root.getScanPlan(v.Type(), true).fields {
+			i, j := fieldPlan.fieldIndex, fieldPlan.leafIndex
+			leafNode := root.leafNodes[j]
+			var setValue interface{}
+			setValue, err = v.Field(i).Interface().(ImportField).Translate(row[j], leafNode.colIndexStart)
+			if err != nil {
+				if root.withHumanErrorMsg {
+					fmt.Printf("scanExRow scan row:(%+v) error:(%+v)", row, err)
+					return leafNode.colIndexStart, errors.Errorf("%s 单元格填写错误，请检查", strings.Join(leafNode.path, "-"))
 				}
+				return leafNode.colIndexStart, err
 			}
+			v.Field(i).Set(reflect.ValueOf(setValue))
 		}
 	}
 	return
@@ -369,16 +451,15 @@ func (root *Importer) RelativeScanExRow(row []string, resps ...interface{}) (sca
 
 /*
 *
-asyncScanRow scan a row to resps async, put the scaned resps into channel
+scanRowAll scan a row to resps without stopping at the first failed cell, the errors of all failed cells are aggregated,
+the resps are only set into result when all cells are scanned successfully
 */
-func (root *Importer) asyncScanRow(row []string, ch chan *AsyncScanExRes, resps ...interface{}) {
-	var err error
-	asyncScanExRes := new(AsyncScanExRes)
+func (root *Importer) scanRowAll(rowIndex int, row []string, resps []interface{}) (res *AsyncScanExRes) {
+	res = &AsyncScanExRes{RowIndex: rowIndex}
 	defer func() {
 		if p := recover(); p != nil {
-			err = fmt.Errorf("asyncScanRow: internal error: %v", p)
-			asyncScanExRes.Err = err
-			ch <- asyncScanExRes
+			res.Resps = nil
+			res.Err = fmt.Errorf("asyncScanRow: internal error: %v", p)
 		}
 	}()
 	if len(root.leafNodes) == 0 {
@@ -395,61 +476,84 @@ func (root *Importer) asyncScanRow(row []string, ch chan *AsyncScanExRes, resps
 			row = append(row, "")
 		}
 	}
+
+	var scanErrs ScanErrors
 	for _, resp := range resps {
-		v := reflect.ValueOf(resp).Elem()
-		for i := 0; i < reflect.Indirect(v).NumField(); i++ {
-			field := reflect.Indirect(v).Type().Field(i)
-			tag := field.Tag.Get("ex")
-			path := strings.Split(tag, "|")
-			for j, leafNode := range root.leafNodes {
-				if reflect.DeepEqual(leafNode.path, path) {
-					var setValue interface{}
-					setValue, err = reflect.Indirect(v).Field(i).Interface().(ImportField).Translate(row[j], leafNode.colIndexStart)
-					if err != nil {
-						asyncScanExRes.Err = err
-						if root.withHumanErrorMsg {
-							fmt.Printf("asyncScanRow scan row:(%+v) error:(%+v)", row, err)
-							asyncScanExRes.Err = errors.Errorf("%s 单元格填写错误，请检查", strings.Join(leafNode.path, "-"))
-						}
-						ch <- asyncScanExRes
-					} else {
-						reflect.Indirect(v).Field(i).Set(reflect.ValueOf(setValue))
-					}
-
-					break
+		v := reflect.Indirect(reflect.ValueOf(resp).Elem())
+		for _, fieldPlan := range root.getScanPlan(v.Type(), false).fields {
+			i, j := fieldPlan.fieldIndex, fieldPlan.leafIndex
+			leafNode := root.leafNodes[j]
+			setValue, err := v.Field(i).Interface().(ImportField).Translate(row[j], leafNode.colIndexStart)
+			if err != nil {
+				scanErr := &ScanError{ColIndex: leafNode.colIndexStart, Path: leafNode.path, Err: err}
+				if root.withHumanErrorMsg {
+					scanErr.Err = errors.Errorf("%s 单元格填写错误，请检查", strings.Join(leafNode.path, "-"))
 				}
+				scanErrs = append(scanErrs, scanErr)
+				continue
 			}
+			v.Field(i).Set(reflect.ValueOf(setValue))
 		}
-		asyncScanExRes.Resps = append(asyncScanExRes.Resps, resp)
 	}
-	ch <- asyncScanExRes
+
+	if len(scanErrs) != 0 {
+		res.ScanErrColIndex = scanErrs[0].ColIndex
+		res.Err = scanErrs
+		return
+	}
+	res.Resps = resps
+	return
+}
+
+/*
+*
+asyncScanRow scan a row to resps async, put exactly one result into channel
+*/
+func (root *Importer) asyncScanRow(row []string, ch chan *AsyncScanExRes, resps ...interface{}) {
+	res := root.scanRowAll(0, row, resps)
+	root.progress.rowScanned(res.Err != nil)
+	ch <- res
 }
 
 /*
 *
-AsyncScanExRows scan rows to resps async
+AsyncScanExRows scan rows to resps async, every row produces exactly one result
 */
 func (root *Importer) AsyncScanExRows(rows [][]string, resps ...interface{}) chan *AsyncScanExRes {
-	pool, _ := ants.NewPool(root.asyncScanWorkerNums)
+	// at least one slot, so that the error of ants.NewPool can be sent without receiver
+	ch := make(chan *AsyncScanExRes, max(len(rows), 1))
+	pool, err := ants.NewPool(root.asyncScanWorkerNums)
+	if err != nil {
+		ch <- &AsyncScanExRes{Err: errors.Wrap(err, "ants.NewPool")}
+		close(ch)
+		return ch
+	}
+	// init the leaf nodes before workers share them
+	if len(root.leafNodes) == 0 {
+		root.leafNodes = root.getLeafNodes()
+	}
 
-	ch := make(chan *AsyncScanExRes, len(rows))
 	var wg sync.WaitGroup
 	for i := range rows {
 		wg.Add(1)
 		var respParams []interface{}
 		for _, resp := range resps {
-			//st := resp
 			respParams = append(respParams, reflect.New(reflect.Indirect(reflect.ValueOf(resp).Elem()).Type()).Interface())
 		}
 		index := i
-		_ = pool.Submit(func() {
+		if err = pool.Submit(func() {
 			defer wg.Done()
 			root.asyncScanRow(rows[index], ch, respParams...)
-		})
+		}); err != nil {
+			wg.Done()
+			ch <- &AsyncScanExRes{Err: errors.Wrap(err, "pool.Submit")}
+		}
 	}
 
 	go func(wg *sync.WaitGroup, ch chan *AsyncScanExRes, wp *ants.Pool) {
 		wg.Wait()
+		// the final progress is reported before the consumer sees the end of results
+		root.progress.flush()
 		close(ch)
 		wp.Release()
 	}(&wg, ch, pool)
@@ -476,6 +580,9 @@ func (root *Importer) IsHeaderConsistent(resps ...interface{}) (isConsistent boo
 			tag := field.Tag.Get("ex")
 			path := strings.Split(tag, "|")
 
+			if lastRespLength+i >= len(root.leafNodes) {
+				return
+			}
 			leafNode := root.leafNodes[lastRespLength+i]
 			if !reflect.DeepEqual(leafNode.path, path) {
 				return
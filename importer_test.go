@@ -0,0 +1,156 @@
+package excel
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/xuri/excelize/v2"
+)
+
+type asyncRow struct {
+	Number StringField `ex:"合同|编号"`
+	Amount FloatField  `ex:"合同|金额"`
+	Count  IntField    `ex:"合同|数量"`
+}
+
+// asyncPanicRow panics in scanning, because a field with ex tag must be an ImportField
+type asyncPanicRow struct {
+	Number string `ex:"合同|编号"`
+}
+
+var _asyncPaths = [][2]string{{"合同", "编号"}, {"合同", "金额"}, {"合同", "数量"}}
+
+func collectAsyncRes(t *testing.T, ch <-chan *AsyncScanExRes) (res []*AsyncScanExRes) {
+	t.Helper()
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case r, ok := <-ch:
+			if !ok {
+				return
+			}
+			res = append(res, r)
+		case <-timeout:
+			t.Fatal("AsyncScanExRows doesn't close the channel")
+		}
+	}
+}
+
+func TestAsyncScanExRowsErrorRows(t *testing.T) {
+	e := newTestExcel(t, _asyncPaths, nil)
+	rows := [][]string{
+		{"HT-001", "10.5", "1"},
+		{"HT-002", "x", "y"},
+		{"HT-003", "30", "3"},
+	}
+
+	var okCount, errCount int
+	for _, res := range collectAsyncRes(t, e.AsyncScanExRows(rows, &asyncRow{})) {
+		if res.Err == nil {
+			okCount++
+			if len(res.Resps) != 1 {
+				t.Fatalf("expect 1 resp, got %d", len(res.Resps))
+			}
+			continue
+		}
+
+		errCount++
+		if res.Resps != nil {
+			t.Fatalf("the resps of a failed row must be nil, got %+v", res.Resps)
+		}
+		var scanErrs ScanErrors
+		if !errors.As(res.Err, &scanErrs) || len(scanErrs) != 2 {
+			t.Fatalf("expect 2 aggregated scan errors, got %v", res.Err)
+		}
+		if res.ScanErrColIndex != scanErrs[0].ColIndex || scanErrs[0].ColIndex != 2 || scanErrs[1].ColIndex != 3 {
+			t.Fatalf("unexpected error cols %d %v", res.ScanErrColIndex, scanErrs)
+		}
+	}
+	if okCount != 2 || errCount != 1 {
+		t.Fatalf("expect exactly one result per row, got %d ok and %d failed", okCount, errCount)
+	}
+}
+
+func TestAsyncScanExRowsRecoverPanic(t *testing.T) {
+	e := newTestExcel(t, _asyncPaths, nil)
+	rows := [][]string{{"HT-001", "10", "1"}, {"HT-002", "20", "2"}}
+
+	res := collectAsyncRes(t, e.AsyncScanExRows(rows, &asyncPanicRow{}))
+	if len(res) != len(rows) {
+		t.Fatalf("expect %d results, got %d", len(rows), len(res))
+	}
+	for _, r := range res {
+		if r.Err == nil || r.Resps != nil {
+			t.Fatalf("expect a recovered panic error without resps, got %+v", r)
+		}
+	}
+}
+
+func TestAsyncScanExRowsWorkerNums(t *testing.T) {
+	rows := [][]string{{"HT-001", "10", "1"}, {"HT-002", "20", "2"}, {"HT-003", "30", "3"}}
+
+	e := newTestExcel(t, _asyncPaths, nil)
+	root := e.SheetImporter(e.activeSheetNames[0])
+	if root.asyncScanWorkerNums != _defaultAsyncScanExRowsGoroutineNums {
+		t.Fatalf("expect default worker nums %d, got %d", _defaultAsyncScanExRowsGoroutineNums, root.asyncScanWorkerNums)
+	}
+
+	e = newTestExcel(t, _asyncPaths, nil, AsyncScanWorkerNums(1))
+	root = e.SheetImporter(e.activeSheetNames[0])
+	if root.asyncScanWorkerNums != 1 || root.SubImporter("合同").asyncScanWorkerNums != 1 {
+		t.Fatalf("expect worker nums 1 in the importer tree, got %d", root.asyncScanWorkerNums)
+	}
+	if res := collectAsyncRes(t, e.AsyncScanExRows(rows, &asyncRow{})); len(res) != len(rows) {
+		t.Fatalf("expect %d results with 1 worker, got %d", len(rows), len(res))
+	}
+}
+
+func TestAsyncScanExRowsInvalidWorkerNums(t *testing.T) {
+	e := newTestExcel(t, _asyncPaths, nil, AsyncScanWorkerNums(-1))
+	for _, rows := range [][][]string{nil, {{"HT-001", "10", "1"}}} {
+		// the pool error is sent before the channel is returned, so it must not block
+		returned := make(chan chan *AsyncScanExRes, 1)
+		go func() { returned <- e.AsyncScanExRows(rows, &asyncRow{}) }()
+		var ch chan *AsyncScanExRes
+		select {
+		case ch = <-returned:
+		case <-time.After(5 * time.Second):
+			t.Fatalf("AsyncScanExRows blocks with %d rows", len(rows))
+		}
+
+		res := collectAsyncRes(t, ch)
+		if len(res) != 1 || res[0].Err == nil {
+			t.Fatalf("expect one pool error result for %d rows, got %+v", len(rows), res)
+		}
+	}
+}
+
+func TestBuildImporterTreeBodyMergeCell(t *testing.T) {
+	// the header 合同 has no merged leaves, and 备注 is merged in the body without beginning at the first col of 合同,
+	// so it's not a child of 合同, ex: the group header of an exported sheet
+	mergeCells := []excelize.MergeCell{{"A1:C1", "合同"}, {"B3:C3", "备注"}}
+	root := &Importer{colIndexStart: 1, colIndexEnd: 3}
+
+	// 合同 taken as its own child recurses without end
+	built := make(chan []*Importer, 1)
+	go func() {
+		children, err := buildImporterTree(root, mergeCells)
+		if err != nil {
+			t.Error(err)
+		}
+		built <- children
+	}()
+	var children []*Importer
+	select {
+	case children = <-built:
+	case <-time.After(5 * time.Second):
+		t.Fatal("buildImporterTree doesn't return")
+	}
+	if len(children) != 1 || children[0].value != "合同" || len(children[0].childImporters) != 0 {
+		t.Fatalf("expect the only leaf 合同, got %+v", children)
+	}
+	if start, end := children[0].GetColIndexPos(); start != 1 || end != 3 {
+		t.Fatalf("expect 合同 spans cols (1,3), got (%d,%d)", start, end)
+	}
+}
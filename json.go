@@ -0,0 +1,338 @@
+package excel
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/xuri/excelize/v2"
+)
+
+type jsonOptions struct {
+	lines bool
+}
+
+type JSONOption func(*jsonOptions)
+
+/*
+*
+JSONLines write a row per line (NDJSON) instead of a JSON array
+*/
+func JSONLines(lines bool) JSONOption {
+	return func(o *jsonOptions) {
+		o.lines = lines
+	}
+}
+
+/*
+*
+ToJSON write the data rows of active sheets to w as nested objects by the header tree, ex:
+{"电子签合同信息":{"基础信息":{"OA合同编号":"HT001"}}}, the values are the cell texts
+*/
+func (e *Excel) ToJSON(w io.Writer, opts ...JSONOption) (err error) {
+	o := new(jsonOptions)
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	bw := bufio.NewWriter(w)
+	if !o.lines {
+		_ = bw.WriteByte('[')
+	}
+	var buf bytes.Buffer
+	var rowCount int
+	for _, sheet := range e.activeSheetNames {
+		root := e.SheetImporter(sheet)
+		var rows [][]string
+		if rows, err = e.GetSheetRowsWithoutHeader(sheet); err != nil {
+			return errors.Wrapf(err, "e.GetSheetRowsWithoutHeader sheet:(%s)", sheet)
+		}
+
+		for _, row := range rows {
+			buf.Reset()
+			if err = root.writeJSONObject(&buf, row); err != nil {
+				return errors.Wrapf(err, "sheet:(%s)", sheet)
+			}
+			if o.lines {
+				buf.WriteByte('\n')
+			} else if rowCount > 0 {
+				_ = bw.WriteByte(',')
+			}
+			if _, err = bw.Write(buf.Bytes()); err != nil {
+				return err
+			}
+			rowCount++
+		}
+	}
+	if !o.lines {
+		_ = bw.WriteByte(']')
+	}
+	return bw.Flush()
+}
+
+/*
+*
+writeJSONObject write the cells of row under root as a JSON object in the header order,
+the children of a node with the same title are rejected, because their keys would be ambiguous
+*/
+func (root *Importer) writeJSONObject(buf *bytes.Buffer, row []string) error {
+	keys := make(map[string]bool, len(root.childImporters))
+	buf.WriteByte('{')
+	for i, child := range root.childImporters {
+		if keys[child.value] {
+			return errors.Errorf("duplicate header %s", strings.Join(append(append([]string{}, root.path...), child.value), "|"))
+		}
+		keys[child.value] = true
+
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		writeJSONString(buf, child.value)
+		buf.WriteByte(':')
+
+		if len(child.childImporters) != 0 {
+			if err := child.writeJSONObject(buf, row); err != nil {
+				return err
+			}
+			continue
+		}
+		var value string
+		if idx := child.colIndexStart - _colIndexStart; idx >= 0 && idx < len(row) {
+			value = row[idx]
+		}
+		writeJSONString(buf, value)
+	}
+	buf.WriteByte('}')
+	return nil
+}
+
+func writeJSONString(buf *bytes.Buffer, s string) {
+	b, _ := json.Marshal(s)
+	buf.Write(b)
+}
+
+/*
+*
+jsonRow the leaf values of a JSON object, key is the joined path
+*/
+type jsonRow map[string]string
+
+/*
+*
+NewExcelFromJSON create excel from a JSON array or NDJSON of nested objects, the header tree is built from the keys
+of objects in order, ex: {"基础信息":{"OA合同编号":"HT001"}} has the header 基础信息|OA合同编号.
+All the leaf values must be at the same depth, the header rows are set to the depth unless option HeaderRow is set
+*/
+func NewExcelFromJSON(reader io.Reader, options ...Option) (e *Excel, err error) {
+	e = newExcel()
+	for _, option := range options {
+		option(e)
+	}
+
+	paths, jsonRows, err := readJSONRows(reader)
+	if err != nil {
+		return nil, fmt.Errorf("read json error:(%+v)", err)
+	}
+	if len(paths) == 0 {
+		return nil, errors.New("no header exist in json")
+	}
+	pathDepth := len(paths[0])
+	for _, path := range paths {
+		if len(path) != pathDepth {
+			return nil, errors.Errorf("path depth is not same, path:(%s)", strings.Join(path, "|"))
+		}
+	}
+	if e.headerRow == 0 {
+		// the leaf headers are not merged cells
+		e.headerRow = pathDepth
+	}
+
+	e.file = excelize.NewFile()
+	sheetName := fmt.Sprintf("%s%d", e.sheetPrefix, 1)
+	if e.sheetPrefix != _defaultSheetPrefix {
+		if _, err = e.file.NewSheet(sheetName); err != nil {
+			return nil, err
+		}
+		// delete default sheet
+		if err = e.file.DeleteSheet("Sheet1"); err != nil {
+			return nil, err
+		}
+	}
+	e.activeSheetNames = []string{sheetName}
+
+	rows := make([]interface{}, len(jsonRows))
+	for i := range jsonRows {
+		rows[i] = jsonRows[i]
+	}
+	initData := func(rows []interface{}) error {
+		return e.initFromJSONRows(paths, rows)
+	}
+	if len(rows) == 0 {
+		// write the header only
+		if err = initData(nil); err != nil {
+			return nil, err
+		}
+	}
+	if err = e.doAfterCreateFile(rows, initData); err != nil {
+		return nil, err
+	}
+	return
+}
+
+func (e *Excel) initFromJSONRows(paths [][]string, rows []interface{}) (err error) {
+	h := &header{isDummy: true, children: getHeadersFromPaths(paths, 0, len(paths[0]))}
+	if _, err = e.writeHeader(h, 1, 0); err != nil {
+		return errors.Wrap(err, "e.writeHeader")
+	}
+	// the leaves are grouped under their parents in header, so the values are written in the order of leaves
+	paths = h.getLeafPaths(nil)
+
+	sheet := e.activeSheetNames[_defaultSheetIndex]
+	dataRow := h.getHeight()
+	for i, row := range rows {
+		values := make([]interface{}, len(paths))
+		for j, path := range paths {
+			values[j] = row.(jsonRow)[strings.Join(path, "|")]
+		}
+		var axis string
+		if axis, err = excelize.CoordinatesToCellName(1, dataRow+i); err != nil {
+			return err
+		}
+		if err = e.file.SetSheetRow(sheet, axis, &values); err != nil {
+			return errors.Wrap(err, "e.file.SetSheetRow")
+		}
+		e.progress.rowWritten(sheet)
+	}
+	e.progress.sheetDone(sheet)
+	return
+}
+
+/*
+*
+readJSONRows read the objects of a JSON array or NDJSON, paths are the leaf paths in the order they first appear
+*/
+func readJSONRows(reader io.Reader) (paths [][]string, rows []jsonRow, err error) {
+	dec := json.NewDecoder(reader)
+	dec.UseNumber()
+
+	pathIndex := make(map[string]bool)
+	readObject := func() error {
+		row := make(jsonRow)
+		if err := readJSONObject(dec, nil, func(path []string, value string) {
+			key := strings.Join(path, "|")
+			if !pathIndex[key] {
+				pathIndex[key] = true
+				paths = append(paths, path)
+			}
+			row[key] = value
+		}); err != nil {
+			return err
+		}
+		rows = append(rows, row)
+		return nil
+	}
+
+	tok, err := dec.Token()
+	if err == io.EOF {
+		return nil, nil, nil
+	}
+	if err != nil {
+		return
+	}
+	if tok == json.Delim('[') {
+		for dec.More() {
+			if tok, err = dec.Token(); err != nil {
+				return
+			}
+			if tok != json.Delim('{') {
+				return nil, nil, errors.Errorf("row %d is not a JSON object", len(rows))
+			}
+			if err = readObject(); err != nil {
+				return
+			}
+		}
+		_, err = dec.Token()
+		return
+	}
+
+	// NDJSON
+	for {
+		if tok != json.Delim('{') {
+			return nil, nil, errors.Errorf("row %d is not a JSON object", len(rows))
+		}
+		if err = readObject(); err != nil {
+			return
+		}
+		if tok, err = dec.Token(); err == io.EOF {
+			return paths, rows, nil
+		} else if err != nil {
+			return
+		}
+	}
+}
+
+/*
+*
+readJSONObject read the members of an object whose '{' is read, fn is called with the path and text of every leaf value
+*/
+func readJSONObject(dec *json.Decoder, prefix []string, fn func(path []string, value string)) error {
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		key, _ := tok.(string)
+		path := append(append([]string{}, prefix...), key)
+
+		if tok, err = dec.Token(); err != nil {
+			return err
+		}
+		switch v := tok.(type) {
+		case json.Delim:
+			if v == '{' {
+				if err = readJSONObject(dec, path, fn); err != nil {
+					return err
+				}
+				continue
+			}
+			// an array is kept as JSON text
+			var value []interface{}
+			if value, err = readJSONArray(dec); err != nil {
+				return err
+			}
+			b, _ := json.Marshal(value)
+			fn(path, string(b))
+		case string:
+			fn(path, v)
+		case json.Number:
+			fn(path, v.String())
+		case bool:
+			fn(path, formatCSVValue(v))
+		case nil:
+			fn(path, "")
+		}
+	}
+	_, err := dec.Token()
+	return err
+}
+
+/*
+*
+readJSONArray read the elements of an array whose '[' is read
+*/
+func readJSONArray(dec *json.Decoder) (res []interface{}, err error) {
+	res = []interface{}{}
+	for dec.More() {
+		var value interface{}
+		if err = dec.Decode(&value); err != nil {
+			return
+		}
+		res = append(res, value)
+	}
+	_, err = dec.Token()
+	return
+}
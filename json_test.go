@@ -0,0 +1,95 @@
+package excel
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestNewExcelFromJSONLeafOrder(t *testing.T) {
+	// a.z appears after b.y, but it's written under its parent a
+	data := `{"a":{"x":"1"},"b":{"y":"2"}}
+{"a":{"x":"3","z":"4"},"b":{"y":"5"}}`
+	e, err := NewExcelFromJSON(strings.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rows, err := e.file.GetRows(e.activeSheetNames[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := [][]string{{"a", "", "b"}, {"x", "z", "y"}, {"1", "", "2"}, {"3", "4", "5"}}
+	if !reflect.DeepEqual(rows, want) {
+		t.Fatalf("expect rows %q, got %q", want, rows)
+	}
+}
+
+func TestNewExcelFromJSONSameLeafTitle(t *testing.T) {
+	data := `[{"甲方":{"名称":"A","比例":"0.6"},"乙方":{"名称":"B","比例":"0.4"}}]`
+	e, err := NewExcelFromJSON(strings.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rows, err := e.file.GetRows(e.activeSheetNames[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := [][]string{{"甲方", "", "乙方"}, {"名称", "比例", "名称", "比例"}, {"A", "0.6", "B", "0.4"}}
+	if !reflect.DeepEqual(rows, want) {
+		t.Fatalf("expect rows %q, got %q", want, rows)
+	}
+}
+
+type jsonSigner struct {
+	FirstName   StringField `ex:"甲方|名称"`
+	FirstRatio  FloatField  `ex:"甲方|比例"`
+	SecondName  StringField `ex:"乙方|名称"`
+	SecondRatio FloatField  `ex:"乙方|比例"`
+}
+
+func TestJSONRoundTrip(t *testing.T) {
+	data := `[{"甲方":{"名称":"A","比例":"0.6"},"乙方":{"名称":"B","比例":"0.4"}},` +
+		`{"甲方":{"名称":"C","比例":"0.5"},"乙方":{"名称":"D","比例":"0.5"}}]`
+	e, err := NewExcelFromJSON(strings.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err = e.ToJSON(&buf); err != nil {
+		t.Fatal(err)
+	}
+	var want, got interface{}
+	if err = json.Unmarshal([]byte(data), &want); err != nil {
+		t.Fatal(err)
+	}
+	if err = json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("expect json %s, got %s", data, buf.String())
+	}
+
+	rows, err := e.GetRowsWithoutHeader()
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp := &jsonSigner{}
+	if _, err = e.ScanExRow(rows[1], resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.FirstName.GetValue() != "C" || resp.SecondName.GetValue() != "D" || resp.SecondRatio.GetValue() != 0.5 {
+		t.Fatalf("unexpected scanned row %+v", resp)
+	}
+}
+
+func TestToJSONDuplicateHeader(t *testing.T) {
+	e := newTestExcel(t, [][2]string{{"合同", "编号"}, {"合同", "编号"}}, [][]interface{}{{"HT-001", "HT-002"}})
+	if err := e.ToJSON(&bytes.Buffer{}); err == nil || !strings.Contains(err.Error(), "duplicate header 合同|编号") {
+		t.Fatalf("expect duplicate header error, got %v", err)
+	}
+}
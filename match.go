@@ -0,0 +1,270 @@
+package excel
+
+import (
+	"reflect"
+	"strings"
+	"unicode"
+)
+
+const (
+	_aliasTag = "exalias"
+)
+
+type MatchKind int
+
+const (
+	// MatchNone the field matches no header
+	MatchNone MatchKind = iota
+	// MatchExact the ex path of field equals the header path
+	MatchExact
+	// MatchAlias the ex path with an alias of field equals the header path
+	MatchAlias
+	// MatchNormalized the path equals the header path after normalization
+	MatchNormalized
+	// MatchFuzzy the last part of the path is similar to the header
+	MatchFuzzy
+)
+
+func (k MatchKind) String() string {
+	switch k {
+	case MatchExact:
+		return "exact"
+	case MatchAlias:
+		return "alias"
+	case MatchNormalized:
+		return "normalized"
+	case MatchFuzzy:
+		return "fuzzy"
+	default:
+		return "none"
+	}
+}
+
+/*
+*
+HeaderMatch report how a struct field matches the header
+*/
+type HeaderMatch struct {
+	// Field is the struct field name
+	Field string
+	// Path is the ex path of the field
+	Path []string
+	// Alias is the alias which matched, empty if the ex path matched
+	Alias string
+	// Header is the path of the matched header
+	Header []string
+	// ColIndex is the col index of the matched header
+	ColIndex int
+	Kind     MatchKind
+	// Similarity is the similarity of the last part of path and header, 1 if not fuzzy matched, 0 if not matched
+	Similarity float64
+}
+
+/*
+*
+fieldPaths return the ex path and the alias paths of a field, the aliases replace the last part of ex path
+*/
+func fieldPaths(field reflect.StructField) (paths [][]string, aliases []string) {
+	tag, ok := field.Tag.Lookup("ex")
+	if !ok {
+		return
+	}
+	path := strings.Split(tag, "|")
+	paths = append(paths, path)
+	aliases = append(aliases, "")
+
+	aliasTag := field.Tag.Get(_aliasTag)
+	if aliasTag == "" {
+		return
+	}
+	for _, alias := range strings.Split(aliasTag, "|") {
+		aliasPath := make([]string, len(path))
+		copy(aliasPath, path)
+		aliasPath[len(aliasPath)-1] = alias
+		paths = append(paths, aliasPath)
+		aliases = append(aliases, alias)
+	}
+	return
+}
+
+/*
+*
+normalizeHeader remove the spaces, turn the full-width chars to half-width and lower the case of header
+*/
+func normalizeHeader(header string) string {
+	var b strings.Builder
+	for _, r := range header {
+		switch {
+		case unicode.IsSpace(r):
+			continue
+		case r >= 0xFF01 && r <= 0xFF5E:
+			r -= 0xFEE0
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+	return b.String()
+}
+
+/*
+*
+similarity return the similarity between a and b based on the levenshtein distance, in [0, 1]
+*/
+func similarity(a, b string) float64 {
+	ra, rb := []rune(a), []rune(b)
+	if len(ra) == 0 && len(rb) == 0 {
+		return 1
+	}
+
+	prev, cur := make([]int, len(rb)+1), make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+
+	return 1 - float64(prev[len(rb)])/float64(max(len(ra), len(rb)))
+}
+
+/*
+*
+alignPath return the part of header path to compare with path, nil if header path is too short
+if relative is false, the header path must have the same length of path
+*/
+func alignPath(header, path []string, relative bool) []string {
+	if len(header) < len(path) || (!relative && len(header) != len(path)) {
+		return nil
+	}
+	return header[len(header)-len(path):]
+}
+
+func pathEqual(header, path []string, normalize bool) bool {
+	for i := range path {
+		if header[i] == path[i] {
+			continue
+		}
+		if !normalize || normalizeHeader(header[i]) != normalizeHeader(path[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+/*
+*
+findLeafNode find the leaf node which the field matches, return the index of the leaf node, -1 if not found
+a field matches by its ex path first, then by its aliases, then by the normalized paths and at last by similarity
+*/
+func (root *Importer) findLeafNode(field reflect.StructField, relative bool) (index int, match *HeaderMatch) {
+	if len(root.leafNodes) == 0 {
+		root.leafNodes = root.getLeafNodes()
+	}
+	paths, aliases := fieldPaths(field)
+	if len(paths) == 0 {
+		return -1, nil
+	}
+	match = &HeaderMatch{Field: field.Name, Path: paths[0], Similarity: 1}
+
+	found := func(i, j int, kind MatchKind) (int, *HeaderMatch) {
+		leafNode := root.leafNodes[i]
+		match.Alias = aliases[j]
+		match.Header = leafNode.path
+		match.ColIndex = leafNode.colIndexStart
+		match.Kind = kind
+		return i, match
+	}
+
+	for j, path := range paths {
+		for i, leafNode := range root.leafNodes {
+			if header := alignPath(leafNode.path, path, relative); header != nil && pathEqual(header, path, false) {
+				kind := MatchExact
+				if j > 0 {
+					kind = MatchAlias
+				}
+				return found(i, j, kind)
+			}
+		}
+	}
+
+	if root.normalizeHeader {
+		for j, path := range paths {
+			for i, leafNode := range root.leafNodes {
+				if header := alignPath(leafNode.path, path, relative); header != nil && pathEqual(header, path, true) {
+					return found(i, j, MatchNormalized)
+				}
+			}
+		}
+	}
+
+	if root.headerSimilarity > 0 {
+		bestI, bestJ, bestSimilarity := -1, -1, 0.0
+		for j, path := range paths {
+			last := len(path) - 1
+			for i, leafNode := range root.leafNodes {
+				header := alignPath(leafNode.path, path, relative)
+				if header == nil || !pathEqual(header[:last], path[:last], true) {
+					continue
+				}
+				s := similarity(normalizeHeader(header[last]), normalizeHeader(path[last]))
+				if s >= root.headerSimilarity && s > bestSimilarity {
+					bestI, bestJ, bestSimilarity = i, j, s
+				}
+			}
+		}
+		if bestI != -1 {
+			match.Similarity = bestSimilarity
+			return found(bestI, bestJ, MatchFuzzy)
+		}
+	}
+
+	match.Kind = MatchNone
+	match.Similarity = 0
+	return -1, match
+}
+
+/*
+*
+MatchHeaders report how the fields of resps match the headers
+Note: resps must be struct pointer types
+*/
+func (root *Importer) MatchHeaders(resps ...interface{}) (matches []*HeaderMatch) {
+	for _, resp := range resps {
+		typ := reflect.TypeOf(resp)
+		for typ.Kind() == reflect.Ptr {
+			typ = typ.Elem()
+		}
+		for i := 0; i < typ.NumField(); i++ {
+			if _, match := root.findLeafNode(typ.Field(i), false); match != nil {
+				matches = append(matches, match)
+			}
+		}
+	}
+	return
+}
+
+/*
+*
+MatchHeaders report how the fields of resps match the headers of the active sheet
+*/
+func (e *Excel) MatchHeaders(resps ...interface{}) []*HeaderMatch {
+	return e.importers[_defaultSheetIndex].MatchHeaders(resps...)
+}
+
+/*
+*
+ColName return the col name of the matched header, ex: "A"
+*/
+func (m *HeaderMatch) ColName() string {
+	if m.ColIndex == 0 {
+		return ""
+	}
+	return colName(m.ColIndex)
+}
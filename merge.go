@@ -0,0 +1,239 @@
+package excel
+
+import (
+	"reflect"
+
+	"github.com/pkg/errors"
+	"github.com/xuri/excelize/v2"
+)
+
+/*
+*
+cellRange the coordinates of a merge cell, all indices begin from 1
+*/
+type cellRange struct {
+	colIndexStart, colIndexEnd int
+	rowIndexStart, rowIndexEnd int
+	value                      string
+}
+
+func newCellRange(mergeCell excelize.MergeCell) (r cellRange, err error) {
+	if r.colIndexStart, r.rowIndexStart, err = excelize.CellNameToCoordinates(mergeCell.GetStartAxis()); err != nil {
+		return
+	}
+	if r.colIndexEnd, r.rowIndexEnd, err = excelize.CellNameToCoordinates(mergeCell.GetEndAxis()); err != nil {
+		return
+	}
+	r.value = mergeCell.GetCellValue()
+	return
+}
+
+/*
+*
+splitBodyMergeCells split merge cells of a sheet into header cells and body cells
+the header is the top rows which every col is covered by merge cells and no merge cell crosses its bottom
+*/
+func splitBodyMergeCells(mergeCells []excelize.MergeCell, lastColIndex int) (headers, body []excelize.MergeCell, err error) {
+	ranges := make([]cellRange, len(mergeCells))
+	var maxRowIndex int
+	for i, mergeCell := range mergeCells {
+		if ranges[i], err = newCellRange(mergeCell); err != nil {
+			return
+		}
+		if ranges[i].rowIndexEnd > maxRowIndex {
+			maxRowIndex = ranges[i].rowIndexEnd
+		}
+	}
+
+	var headerRowIndexEnd int
+	for row := 1; row <= maxRowIndex; row++ {
+		var coveredCols int
+		crossed := false
+		for _, r := range ranges {
+			if r.rowIndexStart <= row && row <= r.rowIndexEnd {
+				coveredCols += r.colIndexEnd - r.colIndexStart + 1
+				if row < r.rowIndexEnd {
+					crossed = true
+				}
+			}
+		}
+		if coveredCols < lastColIndex {
+			break
+		}
+		if !crossed {
+			headerRowIndexEnd = row
+		}
+	}
+
+	for i, r := range ranges {
+		if r.rowIndexEnd <= headerRowIndexEnd {
+			headers = append(headers, mergeCells[i])
+		} else {
+			body = append(body, mergeCells[i])
+		}
+	}
+	return
+}
+
+/*
+*
+getBodyMergeCells get the merge cells of sheet below the header rows
+*/
+func (e *Excel) getBodyMergeCells(sheet string, rowBeginIndex int) (body []cellRange, err error) {
+	mergeCells, err := e.file.GetMergeCells(sheet)
+	if err != nil {
+		return
+	}
+	for _, mergeCell := range mergeCells {
+		var r cellRange
+		if r, err = newCellRange(mergeCell); err != nil {
+			return
+		}
+		if r.rowIndexStart > rowBeginIndex {
+			body = append(body, r)
+		}
+	}
+	return
+}
+
+/*
+*
+fillMergedBodyCells set the value of body merge cells to every cell they span
+rows are all rows of sheet, rowBeginIndex is the beginning row index of data rows
+*/
+func (e *Excel) fillMergedBodyCells(sheet string, rows [][]string, rowBeginIndex int) (res [][]string, err error) {
+	body, err := e.getBodyMergeCells(sheet, rowBeginIndex)
+	if err != nil {
+		return
+	}
+
+	for _, r := range body {
+		for len(rows) < r.rowIndexEnd {
+			rows = append(rows, nil)
+		}
+		for i := r.rowIndexStart - 1; i < r.rowIndexEnd; i++ {
+			for len(rows[i]) < r.colIndexEnd {
+				rows[i] = append(rows[i], "")
+			}
+			for j := r.colIndexStart - 1; j < r.colIndexEnd; j++ {
+				rows[i][j] = r.value
+			}
+		}
+	}
+	return rows, nil
+}
+
+/*
+*
+getSheetDataRows get all rows of sheet, the body merge cells are filled if option FillMergedBodyCells is set
+*/
+func (e *Excel) getSheetDataRows(sheet string, rowBeginIndex int) (rows [][]string, err error) {
+	if rows, err = e.file.GetRows(sheet); err != nil {
+		return
+	}
+	if e.fillMergedBody {
+		if rows, err = e.fillMergedBodyCells(sheet, rows, rowBeginIndex); err != nil {
+			err = errors.Wrap(err, "e.fillMergedBodyCells")
+			return
+		}
+	}
+	return
+}
+
+/*
+*
+ExRowGroup the rows spanned by vertically merged body cells
+*/
+type ExRowGroup struct {
+	// RowIndexStart, RowIndexEnd the excel row indices (begin from 1) of the group
+	RowIndexStart, RowIndexEnd int
+	// Master is scanned from the first row of the group
+	Master interface{}
+	// Details are scanned from every row of the group
+	Details []interface{}
+}
+
+/*
+*
+ScanSheetRowGroups scan rows of sheet into master/detail groups, the rows spanned by the same vertically merged body cells
+make up a group, a row not spanned by any merged cell is a group itself
+Note: master and detail must be struct pointer types, detail can be nil, and option FillMergedBodyCells must be set,
+otherwise the merge cells below the header are part of the header tree
+*/
+func (e *Excel) ScanSheetRowGroups(sheet string, master, detail interface{}) (groups []*ExRowGroup, err error) {
+	if !e.fillMergedBody {
+		err = errors.New("option FillMergedBodyCells is required to scan row groups")
+		return
+	}
+	importer := e.SheetImporter(sheet)
+	if importer == nil {
+		err = errors.Errorf("sheet %s is not active", sheet)
+		return
+	}
+	rowBeginIndex := importer.getRowsBeginIndex()
+
+	rows, err := e.getSheetDataRows(sheet, rowBeginIndex)
+	if err != nil {
+		err = errors.Wrap(err, "e.getSheetDataRows")
+		return
+	}
+	body, err := e.getBodyMergeCells(sheet, rowBeginIndex)
+	if err != nil {
+		err = errors.Wrap(err, "e.getBodyMergeCells")
+		return
+	}
+
+	// groupEnds[i] is the end row index of the group which begins at row index i
+	groupEnds := make(map[int]int)
+	for i := rowBeginIndex + 1; i <= len(rows); i++ {
+		groupEnds[i] = i
+	}
+	for _, r := range body {
+		if r.rowIndexStart == r.rowIndexEnd {
+			continue
+		}
+		start := r.rowIndexStart
+		for ; start > rowBeginIndex; start-- {
+			if _, ok := groupEnds[start]; ok {
+				break
+			}
+		}
+		if groupEnds[start] < r.rowIndexEnd {
+			groupEnds[start] = r.rowIndexEnd
+		}
+		for i := start + 1; i <= r.rowIndexEnd; i++ {
+			if end, ok := groupEnds[i]; ok {
+				if end > groupEnds[start] {
+					groupEnds[start] = end
+				}
+				delete(groupEnds, i)
+			}
+		}
+	}
+
+	for i := rowBeginIndex + 1; i <= len(rows); i++ {
+		end, ok := groupEnds[i]
+		if !ok || len(rows[i-1]) == 0 {
+			continue
+		}
+
+		group := &ExRowGroup{RowIndexStart: i, RowIndexEnd: end}
+		group.Master = reflect.New(reflect.TypeOf(master).Elem()).Interface()
+		if _, err = importer.ScanExRow(rows[i-1], group.Master); err != nil {
+			err = errors.Wrapf(err, "sheet:(%s) row:(%d)", sheet, i)
+			return
+		}
+		if detail != nil {
+			for j := i; j <= end; j++ {
+				d := reflect.New(reflect.TypeOf(detail).Elem()).Interface()
+				if _, err = importer.ScanExRow(rows[j-1], d); err != nil {
+					err = errors.Wrapf(err, "sheet:(%s) row:(%d)", sheet, j)
+					return
+				}
+				group.Details = append(group.Details, d)
+			}
+		}
+		groups = append(groups, group)
+	}
+	return
+}
@@ -1,5 +1,11 @@
 package excel
 
+import (
+	"time"
+
+	"github.com/xuri/excelize/v2"
+)
+
 type Option func(*Excel)
 
 /*
@@ -52,6 +58,26 @@ func AsyncScanWorkerNums(workerNums int) Option {
 	}
 }
 
+/*
+*
+AsyncScanOrdered keep the order of rows for the results of AsyncScanExRowsContext
+*/
+func AsyncScanOrdered(ordered bool) Option {
+	return func(e *Excel) {
+		e.asyncScanOrdered = ordered
+	}
+}
+
+/*
+*
+AsyncScanBufferSize set the buffer size of the results channel of AsyncScanExRowsContext
+*/
+func AsyncScanBufferSize(bufferSize int) Option {
+	return func(e *Excel) {
+		e.asyncScanBufferSize = bufferSize
+	}
+}
+
 /*
 *
 WithHumanErrorMsg set msg for human understanding
@@ -71,3 +97,224 @@ func HeaderRow(headerRow int) Option {
 		e.headerRow = headerRow
 	}
 }
+
+/*
+*
+FillMergedBodyCells set the value of vertically merged cells below the header to every row they span when reading rows,
+and the merge cells below the header are not treated as headers
+*/
+func FillMergedBodyCells(fill bool) Option {
+	return func(e *Excel) {
+		e.fillMergedBody = fill
+	}
+}
+
+/*
+*
+DetectHeader detect the header in the first maxRows rows by matching the ex paths of resps,
+title rows, notes and blank rows above the header are skipped, it's prior to HeaderRow
+*/
+func DetectHeader(maxRows int, resps ...interface{}) Option {
+	return func(e *Excel) {
+		e.headerDetect = newHeaderDetect(maxRows, resps...)
+	}
+}
+
+/*
+*
+NormalizeHeader match the header by ignoring spaces, full-width chars and case when the ex path doesn't match
+*/
+func NormalizeHeader(normalize bool) Option {
+	return func(e *Excel) {
+		e.normalizeHeader = normalize
+	}
+}
+
+/*
+*
+HeaderSimilarity match the header whose last part is similar to the ex path when no header matches exactly,
+threshold is in (0, 1], 0 means disabled
+*/
+func HeaderSimilarity(threshold float64) Option {
+	return func(e *Excel) {
+		e.headerSimilarity = threshold
+	}
+}
+
+/*
+*
+RejectHeaderIssues compare the headers of active sheets with the fields of resps when creating the excel,
+return error if there are issues in reject kinds, ex: RejectHeaderIssues(HeaderMissing|HeaderDepthMismatch, &BaseInfo{})
+*/
+func RejectHeaderIssues(reject HeaderIssueKind, resps ...interface{}) Option {
+	return func(e *Excel) {
+		e.headerCheck = &headerCheck{reject: reject, resps: resps}
+	}
+}
+
+/*
+*
+WithProgress report the progress of import and export to fn
+*/
+func WithProgress(fn func(p Progress)) Option {
+	return func(e *Excel) {
+		e.progressFn = fn
+	}
+}
+
+/*
+*
+ProgressInterval set the min interval between two progress reports, default is 500ms
+*/
+func ProgressInterval(interval time.Duration) Option {
+	return func(e *Excel) {
+		e.progressInterval = interval
+	}
+}
+
+/*
+*
+CSVComma set the field delimiter for NewExcelFromCSV, ex: '\t' for tsv, default is ','
+*/
+func CSVComma(comma rune) Option {
+	return func(e *Excel) {
+		e.csvComma = comma
+	}
+}
+
+/*
+*
+EncryptPassword encrypt the workbook with password when it's saved by SaveAs, WriteTo, Bytes or WriteHTTP,
+option SavePassword overrides it
+*/
+func EncryptPassword(password string) Option {
+	return func(e *Excel) {
+		e.encryptPassword = password
+	}
+}
+
+/*
+*
+ProtectSheet protect the exported sheets with password (can be empty), the header rows can't be edited
+while the data cells are editable except the cols of fields with tag exlock:"true"
+*/
+func ProtectSheet(password string) Option {
+	return func(e *Excel) {
+		e.sheetProtect = &excelize.SheetProtectionOptions{
+			Password:            password,
+			SelectLockedCells:   true,
+			SelectUnlockedCells: true,
+			FormatColumns:       true,
+			FormatRows:          true,
+		}
+	}
+}
+
+func (e *Excel) exportStyle() *exportStyle {
+	if e.style == nil {
+		e.style = &exportStyle{headers: make(map[int]*excelize.Style)}
+	}
+	return e.style
+}
+
+/*
+*
+HeaderStyle set the style of the exported header cells at depth, 0 is the top header row
+*/
+func HeaderStyle(depth int, style *excelize.Style) Option {
+	return func(e *Excel) {
+		e.exportStyle().headers[depth] = style
+	}
+}
+
+/*
+*
+WrapText wrap the text of the exported data cells
+*/
+func WrapText(wrap bool) Option {
+	return func(e *Excel) {
+		e.exportStyle().wrapText = wrap
+	}
+}
+
+/*
+*
+Borders draw thin borders around the exported header and data cells
+*/
+func Borders(borders bool) Option {
+	return func(e *Excel) {
+		e.exportStyle().borders = borders
+	}
+}
+
+/*
+*
+ZebraRows fill every second exported data row with color, ex: "F2F2F2"
+*/
+func ZebraRows(color string) Option {
+	return func(e *Excel) {
+		e.exportStyle().zebraColor = color
+	}
+}
+
+/*
+*
+TimeNumFmt set the number format of the exported time cols without tag exnumfmt, ex: "yyyy-mm-dd",
+the time cols keep the date time format m/d/yy h:mm of excelize by default
+*/
+func TimeNumFmt(numFmt string) Option {
+	return func(e *Excel) {
+		e.exportStyle().timeNumFmt = numFmt
+	}
+}
+
+/*
+*
+AutoFitColWidth fit the width of the exported cols to the headers and values, the tag exwidth is prior to it
+*/
+func AutoFitColWidth(autoFit bool) Option {
+	return func(e *Excel) {
+		e.exportStyle().autoFit = autoFit
+	}
+}
+
+/*
+*
+WithConditionalFormat set the conditional format rules to the exported data cells of the col of ex path,
+ex: WithConditionalFormat("合同|结束时间", DateBeforeTodayRule(&excelize.Style{Font: &excelize.Font{Color: "FF0000"}}))
+*/
+func WithConditionalFormat(path string, rules ...ConditionalRule) Option {
+	return func(e *Excel) {
+		e.conditionalFormats = append(e.conditionalFormats, conditionalFormat{path: path, rules: rules})
+	}
+}
+
+/*
+*
+WithSummary write a summary row below the exported data rows, the aggregates are SUBTOTAL formulas of the cols of
+their ex paths, label (default 合计) is written at the first col which is not aggregated,
+ex: WithSummary("合计", Aggregate{Path: "合同|金额", Func: AggregateSum}, Aggregate{Path: "合同|编号", Func: AggregateCount})
+*/
+func WithSummary(label string, aggregates ...Aggregate) Option {
+	return func(e *Excel) {
+		if label == "" {
+			label = _defaultSummaryLabel
+		}
+		e.summary = &summary{label: label, aggregates: aggregates}
+	}
+}
+
+/*
+*
+GroupBy group the exported rows by the value of the field of ex path, the rows are sorted stably by the value,
+every group begins with a group header row, ex: 签约主体：A公司, and its data rows can be collapsed by the outline.
+If option WithSummary is set, a subtotal row labeled subtotalLabel (default 小计) is written below every group
+*/
+func GroupBy(path, subtotalLabel string) Option {
+	return func(e *Excel) {
+		if subtotalLabel == "" {
+			subtotalLabel = _defaultSubtotalLabel
+		}
+		e.groupBy = &groupBy{path: path, subtotalLabel: subtotalLabel}
+	}
+}
@@ -0,0 +1,111 @@
+package excel
+
+import (
+	"reflect"
+	"strings"
+	"sync"
+)
+
+/*
+*
+exField a struct field with ex tag
+*/
+type exField struct {
+	// index of the field in struct
+	index int
+	// path is the ex path of the field
+	path []string
+	// locked is true if the col of the field is locked in the protected sheet
+	locked bool
+	// width is the col width set by tag exwidth
+	width float64
+	// numFmt is the number format set by tag exnumfmt
+	numFmt string
+}
+
+// _exFieldsCache cache the ex fields of struct types, key is reflect.Type, value is []*exField
+var _exFieldsCache sync.Map
+
+/*
+*
+getExFields return the fields with ex tag of struct type in order, the result is cached by type
+*/
+func getExFields(typ reflect.Type) []*exField {
+	if fields, ok := _exFieldsCache.Load(typ); ok {
+		return fields.([]*exField)
+	}
+
+	var fields []*exField
+	for i := 0; i < typ.NumField(); i++ {
+		tag, ok := typ.Field(i).Tag.Lookup("ex")
+		if !ok {
+			continue
+		}
+		fields = append(fields, &exField{
+			index:  i,
+			path:   strings.Split(tag, "|"),
+			locked: typ.Field(i).Tag.Get(_lockTag) == "true",
+			width:  parseWidthTag(typ.Field(i).Tag.Get(_widthTag)),
+			numFmt: typ.Field(i).Tag.Get(_numFmtTag),
+		})
+	}
+	actual, _ := _exFieldsCache.LoadOrStore(typ, fields)
+	return actual.([]*exField)
+}
+
+/*
+*
+fieldPlan the leaf node which a struct field is scanned from
+*/
+type fieldPlan struct {
+	// index of the field in struct
+	fieldIndex int
+	// index of the leaf node in root.leafNodes, which is also the index of the cell in row
+	leafIndex int
+}
+
+/*
+*
+scanPlan the compiled mapping from a struct type to the leaf nodes of importer
+*/
+type scanPlan struct {
+	fields []fieldPlan
+}
+
+type scanPlanKey struct {
+	typ      reflect.Type
+	relative bool
+}
+
+/*
+*
+getScanPlan return the scan plan of struct type, the plan is built once and cached in importer
+*/
+func (root *Importer) getScanPlan(typ reflect.Type, relative bool) *scanPlan {
+	key := scanPlanKey{typ: typ, relative: relative}
+	if plan, ok := root.scanPlans.Load(key); ok {
+		return plan.(*scanPlan)
+	}
+
+	plan := new(scanPlan)
+	for _, field := range getExFields(typ) {
+		leafIndex, _ := root.findLeafNode(typ.Field(field.index), relative)
+		if leafIndex == -1 {
+			continue
+		}
+		plan.fields = append(plan.fields, fieldPlan{fieldIndex: field.index, leafIndex: leafIndex})
+	}
+	actual, _ := root.scanPlans.LoadOrStore(key, plan)
+	return actual.(*scanPlan)
+}
+
+/*
+*
+resetScanPlans drop the cached scan plans, it must be called when the leaf nodes are changed
+*/
+func (root *Importer) resetScanPlans() {
+	root.scanPlans.Range(func(key, _ interface{}) bool {
+		root.scanPlans.Delete(key)
+		return true
+	})
+}
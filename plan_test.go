@@ -0,0 +1,128 @@
+package excel
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/xuri/excelize/v2"
+)
+
+type benchRow struct {
+	Number  StringField `ex:"合同|编号"`
+	Name    StringField `ex:"合同|名称"`
+	Amount  FloatField  `ex:"合同|金额"`
+	Count   IntField    `ex:"合同|数量"`
+	Signer  StringField `ex:"签约方|甲方"`
+	Partner StringField `ex:"签约方|乙方"`
+	Ratio   FloatField  `ex:"签约方|比例"`
+	Remark  StringField `ex:"签约方|备注"`
+}
+
+/*
+*
+newTestExcel build a workbook whose two header rows are merged from paths, the top titles are merged over their cols
+*/
+func newTestExcel(tb testing.TB, paths [][2]string, rows [][]interface{}, options ...Option) *Excel {
+	tb.Helper()
+	f := excelize.NewFile()
+	sheet := f.GetSheetName(0)
+	for j := 0; j < len(paths); {
+		k := j
+		for k < len(paths) && paths[k][0] == paths[j][0] {
+			k++
+		}
+		hCell, _ := excelize.CoordinatesToCellName(j+1, 1)
+		vCell, _ := excelize.CoordinatesToCellName(k, 1)
+		_ = f.SetCellValue(sheet, hCell, paths[j][0])
+		_ = f.MergeCell(sheet, hCell, vCell)
+		j = k
+	}
+	for j, path := range paths {
+		axis, _ := excelize.CoordinatesToCellName(j+1, 2)
+		_ = f.SetCellValue(sheet, axis, path[1])
+		_ = f.MergeCell(sheet, axis, axis)
+	}
+	for i, row := range rows {
+		axis, _ := excelize.CoordinatesToCellName(1, i+3)
+		_ = f.SetSheetRow(sheet, axis, &row)
+	}
+
+	var buf bytes.Buffer
+	if err := f.Write(&buf); err != nil {
+		tb.Fatal(err)
+	}
+	e, err := NewExcelFromReader(&buf, options...)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	return e
+}
+
+var _benchPaths = [][2]string{
+	{"合同", "编号"}, {"合同", "名称"}, {"合同", "金额"}, {"合同", "数量"},
+	{"签约方", "甲方"}, {"签约方", "乙方"}, {"签约方", "比例"}, {"签约方", "备注"},
+}
+
+/*
+*
+scanExRowUncached scan row the way before scan plans, every field is matched to the leaf nodes by reflection per row
+*/
+func scanExRowUncached(root *Importer, row []string, resp interface{}) error {
+	v := reflect.Indirect(reflect.ValueOf(resp).Elem())
+	for i := 0; i < v.NumField(); i++ {
+		j, _ := root.findLeafNode(v.Type().Field(i), false)
+		if j == -1 {
+			continue
+		}
+		setValue, err := v.Field(i).Interface().(ImportField).Translate(row[j], root.leafNodes[j].colIndexStart)
+		if err != nil {
+			return err
+		}
+		v.Field(i).Set(reflect.ValueOf(setValue))
+	}
+	return nil
+}
+
+func BenchmarkScanExRow(b *testing.B) {
+	e := newTestExcel(b, _benchPaths, nil)
+	root := e.SheetImporter(e.activeSheetNames[0])
+	row := []string{"HT-001", "采购合同", "1024.5", "3", "甲公司", "乙公司", "0.6", "备注"}
+
+	b.Run("cached", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, err := root.ScanExRow(row, &benchRow{}); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("uncached", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if err := scanExRowUncached(root, row, &benchRow{}); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func TestScanExRowPlan(t *testing.T) {
+	e := newTestExcel(t, _benchPaths, nil)
+	root := e.SheetImporter(e.activeSheetNames[0])
+	row := []string{"HT-001", "采购合同", "1024.5", "3", "甲公司", "乙公司", "0.6", "备注"}
+
+	cached, uncached := &benchRow{}, &benchRow{}
+	if _, err := root.ScanExRow(row, cached); err != nil {
+		t.Fatal(err)
+	}
+	if err := scanExRowUncached(root, row, uncached); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(cached, uncached) {
+		t.Fatalf("cached scan %+v differs from uncached scan %+v", cached, uncached)
+	}
+	if cached.Amount.GetValue() != 1024.5 || cached.Partner.GetValue() != "乙公司" {
+		t.Fatalf("unexpected scan result %+v", cached)
+	}
+}
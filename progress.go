@@ -0,0 +1,176 @@
+package excel
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/xuri/excelize/v2"
+)
+
+const (
+	_defaultProgressInterval = 500 * time.Millisecond
+)
+
+/*
+*
+Progress the progress of import or export
+*/
+type Progress struct {
+	// Sheet is the sheet being processed
+	Sheet string
+	// SheetsDone is the count of sheets which are read or written
+	SheetsDone int
+	// SheetsTotal is the count of active sheets
+	SheetsTotal int
+	// TotalRows is the estimated count of data rows, estimated from the sheet dimensions when importing
+	TotalRows int
+	// RowsRead is the count of rows read by the row iterators, ex: ForEachRow
+	RowsRead int
+	// RowsScanned is the count of rows scanned async
+	RowsScanned int
+	// RowsWritten is the count of data rows written
+	RowsWritten int
+	// Errors is the count of rows failed to scan
+	Errors int
+}
+
+/*
+*
+progressReporter report the progress to fn at most once every interval, it's safe for concurrent use
+and fn is called serially in the order of updates, a nil progressReporter reports nothing
+*/
+type progressReporter struct {
+	fn       func(p Progress)
+	interval time.Duration
+	// fnMu serialize the calls of fn, it's locked before mu is unlocked to keep the order of reports
+	fnMu sync.Mutex
+
+	mu       sync.Mutex
+	progress Progress
+	last     time.Time
+	// the sheets which are reported done, a sheet is counted in SheetsDone only once
+	doneSheets map[string]bool
+}
+
+/*
+*
+update change the progress by f and report it if interval elapsed or force is true
+*/
+func (r *progressReporter) update(f func(p *Progress), force bool) {
+	if r == nil {
+		return
+	}
+
+	r.mu.Lock()
+	f(&r.progress)
+	now := time.Now()
+	if !force && now.Sub(r.last) < r.interval {
+		r.mu.Unlock()
+		return
+	}
+	r.last = now
+	p := r.progress
+	r.fnMu.Lock()
+	r.mu.Unlock()
+
+	defer r.fnMu.Unlock()
+	r.fn(p)
+}
+
+func (r *progressReporter) rowRead(sheet string) {
+	r.update(func(p *Progress) {
+		p.Sheet = sheet
+		p.RowsRead++
+	}, false)
+}
+
+func (r *progressReporter) rowScanned(failed bool) {
+	r.update(func(p *Progress) {
+		p.RowsScanned++
+		if failed {
+			p.Errors++
+		}
+	}, false)
+}
+
+func (r *progressReporter) rowWritten(sheet string) {
+	r.update(func(p *Progress) {
+		p.Sheet = sheet
+		p.RowsWritten++
+	}, false)
+}
+
+func (r *progressReporter) sheetDone(sheet string) {
+	r.update(func(p *Progress) {
+		p.Sheet = sheet
+		if r.doneSheets[sheet] {
+			return
+		}
+		if r.doneSheets == nil {
+			r.doneSheets = make(map[string]bool)
+		}
+		r.doneSheets[sheet] = true
+		p.SheetsDone++
+	}, true)
+}
+
+/*
+*
+flush report the current progress immediately
+*/
+func (r *progressReporter) flush() {
+	r.update(func(p *Progress) {}, true)
+}
+
+/*
+*
+initProgress set the totals of progress, the total rows of imported sheets are estimated from the sheet dimensions
+*/
+func (e *Excel) initProgress(rows []interface{}) {
+	if e.progress == nil {
+		return
+	}
+
+	totalRows := len(rows)
+	if len(rows) == 0 {
+		for i, sheet := range e.activeSheetNames {
+			totalRows += e.estimateSheetRows(sheet, e.importers[i].getRowsBeginIndex())
+		}
+	}
+	e.progress.update(func(p *Progress) {
+		p.SheetsTotal = len(e.activeSheetNames)
+		p.TotalRows = totalRows
+	}, true)
+}
+
+/*
+*
+estimateSheetRows estimate the count of data rows from the sheet dimension, ex: "A1:D100",
+the rows are counted if the sheet has no dimension range
+*/
+func (e *Excel) estimateSheetRows(sheet string, rowBeginIndex int) int {
+	var rowIndexEnd int
+	dimension, err := e.file.GetSheetDimension(sheet)
+	// a single cell dimension is not trusted, excelize writes "A1" whatever the rows are
+	if err == nil && strings.Contains(dimension, ":") {
+		cell := dimension[strings.LastIndex(dimension, ":")+1:]
+		if _, rowIndexEnd, err = excelize.CellNameToCoordinates(cell); err != nil {
+			return 0
+		}
+	} else {
+		rows, err := e.file.Rows(sheet)
+		if err != nil {
+			return 0
+		}
+		for rows.Next() {
+			rowIndexEnd++
+		}
+		_ = rows.Close()
+	}
+
+	if rowIndexEnd <= rowBeginIndex {
+		return 0
+	}
+	return rowIndexEnd - rowBeginIndex
+}
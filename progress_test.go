@@ -0,0 +1,45 @@
+package excel
+
+import (
+	"context"
+	"testing"
+)
+
+// the tests are meaningful with -race, the callback writes a captured variable which is read after return
+
+func TestProgressFlushedBeforeReturn(t *testing.T) {
+	data := [][]interface{}{{"HT-1", 10, 1}, {"HT-2", 20, 2}, {"HT-3", 30, 3}}
+	rows := [][]string{{"HT-1", "10", "1"}, {"HT-2", "20", "2"}, {"HT-3", "30", "3"}}
+
+	cases := map[string]func(e *Excel) error{
+		"AsyncScanExRows": func(e *Excel) error {
+			for range e.AsyncScanExRows(rows, &asyncRow{}) {
+			}
+			return nil
+		},
+		"AsyncScanExRowsContext": func(e *Excel) error {
+			ch, err := e.AsyncScanExRowsContext(context.Background(), NewRowIterator(rows, 3), &asyncRow{})
+			if err != nil {
+				return err
+			}
+			for range ch {
+			}
+			return nil
+		},
+		"ForEachRow": func(e *Excel) error {
+			return ForEachRow(context.Background(), e, func(batch []*asyncRow, meta BatchMeta) error { return nil })
+		},
+	}
+	for name, scan := range cases {
+		t.Run(name, func(t *testing.T) {
+			var last Progress
+			e := newTestExcel(t, _asyncPaths, data, WithProgress(func(p Progress) { last = p }))
+			if err := scan(e); err != nil {
+				t.Fatal(err)
+			}
+			if last.RowsScanned != len(rows) {
+				t.Fatalf("expect the final progress of %d rows before return, got %+v", len(rows), last)
+			}
+		})
+	}
+}
@@ -0,0 +1,100 @@
+package excel
+
+import (
+	"github.com/pkg/errors"
+	"github.com/xuri/excelize/v2"
+)
+
+const (
+	_lockTag = "exlock"
+)
+
+/*
+*
+protectSheet protect sheet by option ProtectSheet, the header rows and the cols of locked fields are locked,
+the other data cells and the empty cells below them are unlocked, the existing styles of cells are kept
+*/
+func (e *Excel) protectSheet(sheet string, headerHeight int, fields []*exField) (err error) {
+	rows, err := e.file.GetRows(sheet)
+	if err != nil {
+		return errors.Wrap(err, "e.file.GetRows")
+	}
+	lastRow := max(len(rows), headerHeight)
+
+	unlockedStyle, err := e.newStyle(&excelize.Style{Protection: &excelize.Protection{Locked: false}})
+	if err != nil {
+		return errors.Wrap(err, "e.newStyle")
+	}
+	// the cells of style 0 take the col style, so they are locked explicitly
+	lockedStyle, err := e.newStyle(&excelize.Style{Protection: &excelize.Protection{Locked: true}})
+	if err != nil {
+		return errors.Wrap(err, "e.newStyle")
+	}
+	// key is the style id of cell, value is the unlocked style id derived from it
+	unlockedStyles := map[int]int{0: unlockedStyle}
+
+	for j, field := range fields {
+		if field.locked {
+			continue
+		}
+		col := j + 1
+		colName, err := excelize.ColumnNumberToName(col)
+		if err != nil {
+			return err
+		}
+
+		// SetColStyle overwrites the styles of existing cells, so keep them to restore
+		styles := make([]int, lastRow+1)
+		for row := 1; row <= lastRow; row++ {
+			axis, _ := excelize.CoordinatesToCellName(col, row)
+			if styles[row], err = e.file.GetCellStyle(sheet, axis); err != nil {
+				return errors.Wrap(err, "e.file.GetCellStyle")
+			}
+		}
+		if err = e.file.SetColStyle(sheet, colName, unlockedStyle); err != nil {
+			return errors.Wrap(err, "e.file.SetColStyle")
+		}
+
+		for row := 1; row <= lastRow; row++ {
+			style := styles[row]
+			if row > headerHeight {
+				if style, err = e.unlockedStyle(style, unlockedStyles); err != nil {
+					return err
+				}
+			} else if style == 0 {
+				style = lockedStyle
+			}
+			axis, _ := excelize.CoordinatesToCellName(col, row)
+			if err = e.file.SetCellStyle(sheet, axis, axis, style); err != nil {
+				return errors.Wrap(err, "e.file.SetCellStyle")
+			}
+		}
+	}
+
+	if err = e.file.ProtectSheet(sheet, e.sheetProtect); err != nil {
+		return errors.Wrap(err, "e.file.ProtectSheet")
+	}
+	return
+}
+
+/*
+*
+unlockedStyle return the style which is the same as style but unlocked, the styles created are cached in unlockedStyles
+*/
+func (e *Excel) unlockedStyle(style int, unlockedStyles map[int]int) (int, error) {
+	if unlocked, ok := unlockedStyles[style]; ok {
+		return unlocked, nil
+	}
+
+	s, err := e.file.GetStyle(style)
+	if err != nil {
+		return 0, errors.Wrap(err, "e.file.GetStyle")
+	}
+	s.Protection = &excelize.Protection{Locked: false}
+	unlocked, err := e.newStyle(s)
+	if err != nil {
+		return 0, errors.Wrap(err, "e.newStyle")
+	}
+	unlockedStyles[style] = unlocked
+	return unlocked, nil
+}
@@ -0,0 +1,271 @@
+package excel
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	_refTag         = "exref"
+	_refTagRequired = "required"
+)
+
+/*
+*
+refTag the parsed exref tag, ex: `exref:"付款计划.OA合同编号,required"`
+sheet - the sheet of child rows
+keyPath - the ex path (or the end of it) of the key field in both child and parent struct
+required - report a RefError if a parent row has no child row
+*/
+type refTag struct {
+	sheet    string
+	keyPath  []string
+	required bool
+}
+
+func parseRefTag(tag string) (ref *refTag, err error) {
+	opts := strings.Split(tag, ",")
+	idx := strings.Index(opts[0], ".")
+	if idx <= 0 || idx == len(opts[0])-1 {
+		return nil, errors.Errorf("exref tag %s is invalid, it should be like sheet.path", tag)
+	}
+
+	ref = &refTag{
+		sheet:   opts[0][:idx],
+		keyPath: strings.Split(opts[0][idx+1:], "|"),
+	}
+	for _, opt := range opts[1:] {
+		switch opt {
+		case _refTagRequired:
+			ref.required = true
+		default:
+			return nil, errors.Errorf("exref tag %s has unknown option %s", tag, opt)
+		}
+	}
+	return
+}
+
+/*
+*
+RefError a row which is failed to be linked between parent sheet and child sheet
+*/
+type RefError struct {
+	Sheet string
+	Row   int
+	Key   string
+	Msg   string
+}
+
+func (e *RefError) Error() string {
+	return fmt.Sprintf("sheet:(%s) row:(%d) key:(%s) %s", e.Sheet, e.Row, e.Key, e.Msg)
+}
+
+/*
+*
+RefErrors all rows which are failed to be linked
+*/
+type RefErrors []*RefError
+
+func (es RefErrors) Error() string {
+	msgs := make([]string, 0, len(es))
+	for _, e := range es {
+		msgs = append(msgs, e.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+/*
+*
+findFieldByPath return the index of the field whose ex path ends with path, -1 if not found
+*/
+func findFieldByPath(typ reflect.Type, path []string) int {
+	for i := 0; i < typ.NumField(); i++ {
+		tag := typ.Field(i).Tag.Get("ex")
+		if tag == "" {
+			continue
+		}
+		fieldPath := strings.Split(tag, "|")
+		if len(fieldPath) >= len(path) && reflect.DeepEqual(fieldPath[len(fieldPath)-len(path):], path) {
+			return i
+		}
+	}
+	return -1
+}
+
+func refKey(v reflect.Value, fieldIndex int) string {
+	importField, ok := v.Elem().Field(fieldIndex).Interface().(ImportField)
+	if !ok {
+		return ""
+	}
+	return fmt.Sprint(importField.GetValue())
+}
+
+/*
+*
+refParent the parent rows of a sheet which hold an exref field
+*/
+type refParent struct {
+	sheet *SheetData
+	rows  reflect.Value
+}
+
+/*
+*
+refField an exref field of parent struct, the parent rows of all sheets are linked together by it,
+so that a child row linked by any parent sheet is not an orphan
+*/
+type refField struct {
+	parentType reflect.Type
+	index      int
+}
+
+/*
+*
+linkSheets assemble child rows into the exref slice fields of parent rows
+*/
+func linkSheets(sheets []*SheetData) (err error) {
+	var (
+		fields  []refField
+		parents = make(map[refField][]refParent)
+	)
+	for _, parent := range sheets {
+		for _, slice := range parent.Slices {
+			parentRows := reflect.ValueOf(slice)
+			parentType := parentRows.Type().Elem().Elem()
+			for j := 0; j < parentType.NumField(); j++ {
+				if _, ok := parentType.Field(j).Tag.Lookup(_refTag); !ok {
+					continue
+				}
+
+				field := refField{parentType: parentType, index: j}
+				if _, ok := parents[field]; !ok {
+					fields = append(fields, field)
+				}
+				parents[field] = append(parents[field], refParent{sheet: parent, rows: parentRows})
+			}
+		}
+	}
+
+	var refErrs RefErrors
+	for _, field := range fields {
+		var errs RefErrors
+		if errs, err = linkField(parents[field], field, sheets); err != nil {
+			err = errors.Wrapf(err, "sheet:(%s) field:(%s)", refParentSheets(parents[field]), field.parentType.Field(field.index).Name)
+			return
+		}
+		refErrs = append(refErrs, errs...)
+	}
+
+	if len(refErrs) != 0 {
+		return refErrs
+	}
+	return
+}
+
+func refParentSheets(parents []refParent) string {
+	names := make([]string, 0, len(parents))
+	for _, parent := range parents {
+		names = append(names, parent.sheet.Sheet)
+	}
+	return strings.Join(names, ",")
+}
+
+func linkField(parents []refParent, refField refField, sheets []*SheetData) (refErrs RefErrors, err error) {
+	parentType, fieldIndex := refField.parentType, refField.index
+	ref, err := parseRefTag(parentType.Field(fieldIndex).Tag.Get(_refTag))
+	if err != nil {
+		return
+	}
+
+	fieldType := parentType.Field(fieldIndex).Type
+	if fieldType.Kind() != reflect.Slice || fieldType.Elem().Kind() != reflect.Ptr || fieldType.Elem().Elem().Kind() != reflect.Struct {
+		err = errors.Errorf("exref field must be a slice of struct pointers, got %s", fieldType)
+		return
+	}
+	parentKeyIndex := findFieldByPath(parentType, ref.keyPath)
+	if parentKeyIndex == -1 {
+		err = errors.Errorf("key %s doesn't exist in %s", strings.Join(ref.keyPath, "|"), parentType)
+		return
+	}
+	childKeyIndex := findFieldByPath(fieldType.Elem().Elem(), ref.keyPath)
+	if childKeyIndex == -1 {
+		err = errors.Errorf("key %s doesn't exist in %s", strings.Join(ref.keyPath, "|"), fieldType.Elem().Elem())
+		return
+	}
+
+	type childRow struct {
+		sheet  string
+		row    int
+		value  reflect.Value
+		linked bool
+	}
+	var (
+		childSheetExist bool
+		childRows       []*childRow
+		keyChildren     = make(map[string][]*childRow)
+	)
+	for _, child := range sheets {
+		if child.Sheet != ref.sheet && child.Schema.Name != ref.sheet {
+			continue
+		}
+		childSheetExist = true
+
+		for _, slice := range child.Slices {
+			rows := reflect.ValueOf(slice)
+			if rows.Type() != fieldType {
+				continue
+			}
+			for k := 0; k < rows.Len(); k++ {
+				row := &childRow{sheet: child.Sheet, row: child.RowIndices[k], value: rows.Index(k)}
+				key := refKey(row.value, childKeyIndex)
+				childRows = append(childRows, row)
+				keyChildren[key] = append(keyChildren[key], row)
+			}
+		}
+	}
+	if !childSheetExist {
+		err = errors.Errorf("referenced sheet %s doesn't exist", ref.sheet)
+		return
+	}
+
+	for _, parent := range parents {
+		for k := 0; k < parent.rows.Len(); k++ {
+			parentRow := parent.rows.Index(k)
+			key := refKey(parentRow, parentKeyIndex)
+			children := keyChildren[key]
+			if len(children) == 0 {
+				if ref.required {
+					refErrs = append(refErrs, &RefError{
+						Sheet: parent.sheet.Sheet,
+						Row:   parent.sheet.RowIndices[k],
+						Key:   key,
+						Msg:   fmt.Sprintf("missing reference rows in sheet %s", ref.sheet),
+					})
+				}
+				continue
+			}
+
+			field := parentRow.Elem().Field(fieldIndex)
+			for _, child := range children {
+				field.Set(reflect.Append(field, child.value))
+				child.linked = true
+			}
+		}
+	}
+
+	// the orphans are reported once after the rows of all parent sheets are linked
+	for _, child := range childRows {
+		if !child.linked {
+			refErrs = append(refErrs, &RefError{
+				Sheet: child.sheet,
+				Row:   child.row,
+				Key:   refKey(child.value, childKeyIndex),
+				Msg:   fmt.Sprintf("orphan row, no parent row in sheet %s", refParentSheets(parents)),
+			})
+		}
+	}
+	return
+}
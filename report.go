@@ -0,0 +1,285 @@
+package excel
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/xuri/excelize/v2"
+)
+
+const (
+	// _renameCandidateSimilarity the min similarity of a unexpected header to be a rename candidate of a missing field
+	_renameCandidateSimilarity = 0.5
+)
+
+type HeaderIssueKind int
+
+const (
+	// HeaderMissing no header matches the field
+	HeaderMissing HeaderIssueKind = 1 << iota
+	// HeaderUnexpected no field matches the header
+	HeaderUnexpected
+	// HeaderReordered the header of field is before the header of the previous field
+	HeaderReordered
+	// HeaderRenamed the header of field is matched by normalization or similarity, or a unexpected header is similar to a missing field
+	HeaderRenamed
+	// HeaderDepthMismatch the header path ends with the field path or the field path ends with the header path, but the depth is different
+	HeaderDepthMismatch
+
+	HeaderIssueAll = HeaderMissing | HeaderUnexpected | HeaderReordered | HeaderRenamed | HeaderDepthMismatch
+)
+
+func (k HeaderIssueKind) String() string {
+	switch k {
+	case HeaderMissing:
+		return "missing"
+	case HeaderUnexpected:
+		return "unexpected"
+	case HeaderReordered:
+		return "reordered"
+	case HeaderRenamed:
+		return "renamed"
+	case HeaderDepthMismatch:
+		return "depth mismatch"
+	default:
+		return fmt.Sprintf("HeaderIssueKind(%d)", int(k))
+	}
+}
+
+/*
+*
+HeaderIssue a difference between the struct fields and the headers
+*/
+type HeaderIssue struct {
+	Kind HeaderIssueKind
+	// Field is the struct field name, empty for unexpected header
+	Field string
+	// Path is the ex path of the field
+	Path []string
+	// Header is the path of the header, empty for missing field
+	Header []string
+	// ColName is the col name of the header, ex: "A"
+	ColName string
+}
+
+func (i *HeaderIssue) String() string {
+	switch i.Kind {
+	case HeaderMissing:
+		return fmt.Sprintf("%s column %s of field %s", i.Kind, strings.Join(i.Path, "-"), i.Field)
+	case HeaderUnexpected:
+		return fmt.Sprintf("%s column %s %s", i.Kind, i.ColName, strings.Join(i.Header, "-"))
+	default:
+		return fmt.Sprintf("%s column %s %s of field %s %s", i.Kind, i.ColName, strings.Join(i.Header, "-"), i.Field, strings.Join(i.Path, "-"))
+	}
+}
+
+/*
+*
+HeaderReport the differences between the struct fields and the headers
+*/
+type HeaderReport struct {
+	Issues []*HeaderIssue
+}
+
+/*
+*
+Has report whether there is an issue in kinds
+*/
+func (r *HeaderReport) Has(kinds HeaderIssueKind) bool {
+	for _, issue := range r.Issues {
+		if issue.Kind&kinds != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+/*
+*
+Check return an error with all issues in reject kinds, nil if there is no such issue
+*/
+func (r *HeaderReport) Check(reject HeaderIssueKind) error {
+	var msgs []string
+	for _, issue := range r.Issues {
+		if issue.Kind&reject != 0 {
+			msgs = append(msgs, issue.String())
+		}
+	}
+	if len(msgs) == 0 {
+		return nil
+	}
+	return errors.Errorf("header is inconsistent: %s", strings.Join(msgs, "; "))
+}
+
+func colName(colIndex int) string {
+	name, _ := excelize.ColumnNumberToName(colIndex)
+	return name
+}
+
+/*
+*
+CompareHeaders compare the fields of resps with the headers in order
+Note: resps must be struct pointer types
+*/
+func (root *Importer) CompareHeaders(resps ...interface{}) (report *HeaderReport) {
+	if len(root.leafNodes) == 0 {
+		root.leafNodes = root.getLeafNodes()
+	}
+	report = new(HeaderReport)
+
+	type missingField struct {
+		field reflect.StructField
+		path  []string
+	}
+	var (
+		used          = make([]bool, len(root.leafNodes))
+		missing       []missingField
+		lastLeafIndex = -1
+	)
+	for _, resp := range resps {
+		typ := reflect.TypeOf(resp)
+		for typ.Kind() == reflect.Ptr {
+			typ = typ.Elem()
+		}
+		for i := 0; i < typ.NumField(); i++ {
+			field := typ.Field(i)
+			j, match := root.findLeafNode(field, false)
+			if match == nil {
+				continue
+			}
+			if j == -1 {
+				if k := root.findDepthMismatchLeafNode(field); k != -1 && !used[k] {
+					used[k] = true
+					report.Issues = append(report.Issues, &HeaderIssue{
+						Kind:    HeaderDepthMismatch,
+						Field:   field.Name,
+						Path:    match.Path,
+						Header:  root.leafNodes[k].path,
+						ColName: colName(root.leafNodes[k].colIndexStart),
+					})
+					continue
+				}
+				missing = append(missing, missingField{field: field, path: match.Path})
+				continue
+			}
+
+			used[j] = true
+			if match.Kind == MatchNormalized || match.Kind == MatchFuzzy {
+				report.Issues = append(report.Issues, &HeaderIssue{
+					Kind:    HeaderRenamed,
+					Field:   field.Name,
+					Path:    match.Path,
+					Header:  match.Header,
+					ColName: match.ColName(),
+				})
+			}
+			if j < lastLeafIndex {
+				report.Issues = append(report.Issues, &HeaderIssue{
+					Kind:    HeaderReordered,
+					Field:   field.Name,
+					Path:    match.Path,
+					Header:  match.Header,
+					ColName: match.ColName(),
+				})
+			}
+			lastLeafIndex = max(lastLeafIndex, j)
+		}
+	}
+
+	// a missing field may be renamed to one of the unexpected headers
+	for _, m := range missing {
+		last := m.path[len(m.path)-1]
+		candidate, bestSimilarity := -1, 0.0
+		for k, leafNode := range root.leafNodes {
+			if used[k] || len(leafNode.path) == 0 {
+				continue
+			}
+			s := similarity(normalizeHeader(leafNode.path[len(leafNode.path)-1]), normalizeHeader(last))
+			if s >= _renameCandidateSimilarity && s > bestSimilarity {
+				candidate, bestSimilarity = k, s
+			}
+		}
+		if candidate == -1 {
+			report.Issues = append(report.Issues, &HeaderIssue{
+				Kind:  HeaderMissing,
+				Field: m.field.Name,
+				Path:  m.path,
+			})
+			continue
+		}
+
+		used[candidate] = true
+		report.Issues = append(report.Issues, &HeaderIssue{
+			Kind:    HeaderRenamed,
+			Field:   m.field.Name,
+			Path:    m.path,
+			Header:  root.leafNodes[candidate].path,
+			ColName: colName(root.leafNodes[candidate].colIndexStart),
+		})
+	}
+
+	for k, leafNode := range root.leafNodes {
+		if !used[k] {
+			report.Issues = append(report.Issues, &HeaderIssue{
+				Kind:    HeaderUnexpected,
+				Header:  leafNode.path,
+				ColName: colName(leafNode.colIndexStart),
+			})
+		}
+	}
+	return
+}
+
+/*
+*
+findDepthMismatchLeafNode find the leaf node whose path ends with the field path, or the field path ends with,
+return the index of the leaf node, -1 if not found
+*/
+func (root *Importer) findDepthMismatchLeafNode(field reflect.StructField) int {
+	// the header is deeper than the field
+	if k, _ := root.findLeafNode(field, true); k != -1 {
+		return k
+	}
+
+	// the header is shallower than the field
+	paths, _ := fieldPaths(field)
+	for _, path := range paths {
+		for k, leafNode := range root.leafNodes {
+			if l := len(leafNode.path); l > 0 && l < len(path) && pathEqual(path[len(path)-l:], leafNode.path, false) {
+				return k
+			}
+		}
+	}
+	return -1
+}
+
+/*
+*
+CompareHeaders compare the fields of resps with the headers of the active sheet in order
+*/
+func (e *Excel) CompareHeaders(resps ...interface{}) *HeaderReport {
+	return e.importers[_defaultSheetIndex].CompareHeaders(resps...)
+}
+
+/*
+*
+headerCheck the header issues which are rejected when creating the excel
+*/
+type headerCheck struct {
+	reject HeaderIssueKind
+	resps  []interface{}
+}
+
+func (e *Excel) checkHeaders() error {
+	if e.headerCheck == nil {
+		return nil
+	}
+	for i, importer := range e.importers {
+		if err := importer.CompareHeaders(e.headerCheck.resps...).Check(e.headerCheck.reject); err != nil {
+			return errors.Wrapf(err, "sheet:(%s)", e.activeSheetNames[i])
+		}
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+package excel
+
+import (
+	"reflect"
+	"testing"
+)
+
+// deeperHeaderRow the header 合同|编号 is deeper than the field path 编号
+type deeperHeaderRow struct {
+	Number StringField `ex:"编号"`
+	Amount FloatField  `ex:"合同|金额"`
+	Count  IntField    `ex:"合同|数量"`
+}
+
+// shallowerHeaderRow the header 合同|编号 is shallower than the field path 电子签|合同|编号
+type shallowerHeaderRow struct {
+	Number StringField `ex:"电子签|合同|编号"`
+	Amount FloatField  `ex:"合同|金额"`
+	Count  IntField    `ex:"合同|数量"`
+}
+
+func TestCompareHeadersDepthMismatch(t *testing.T) {
+	e := newTestExcel(t, _asyncPaths, nil)
+	cases := map[string]struct {
+		resp interface{}
+		path []string
+	}{
+		"deeper header":    {resp: &deeperHeaderRow{}, path: []string{"编号"}},
+		"shallower header": {resp: &shallowerHeaderRow{}, path: []string{"电子签", "合同", "编号"}},
+	}
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			report := e.CompareHeaders(c.resp)
+			want := []*HeaderIssue{{
+				Kind:    HeaderDepthMismatch,
+				Field:   "Number",
+				Path:    c.path,
+				Header:  []string{"合同", "编号"},
+				ColName: "A",
+			}}
+			if !reflect.DeepEqual(report.Issues, want) {
+				t.Fatalf("expect issues %v, got %v", want, report.Issues)
+			}
+			if err := report.Check(HeaderDepthMismatch); err == nil {
+				t.Fatal("expect the depth mismatch is rejected")
+			}
+			if err := report.Check(HeaderRenamed | HeaderMissing | HeaderUnexpected); err != nil {
+				t.Fatalf("expect only the depth mismatch, got %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,261 @@
+package excel
+
+import (
+	"archive/zip"
+	"bytes"
+	"compress/flate"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/xuri/excelize/v2"
+)
+
+const (
+	_xlsxContentType = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+)
+
+// _workbookExts the extensions of workbook which excelize can save
+var _workbookExts = map[string]bool{".xlsx": true, ".xlsm": true, ".xltx": true, ".xltm": true, ".xlam": true}
+
+type saveOptions struct {
+	password string
+	// compress the xlsx parts at compressionLevel if compression is true
+	compression      bool
+	compressionLevel int
+}
+
+type SaveOption func(*saveOptions)
+
+/*
+*
+SavePassword encrypt the saved workbook with password
+*/
+func SavePassword(password string) SaveOption {
+	return func(o *saveOptions) {
+		o.password = password
+	}
+}
+
+/*
+*
+SaveCompression set the compression level of the xlsx parts, from flate.NoCompression to flate.BestCompression,
+the default is the default level of deflate
+*/
+func SaveCompression(level int) SaveOption {
+	return func(o *saveOptions) {
+		o.compression = true
+		o.compressionLevel = level
+	}
+}
+
+func (e *Excel) newSaveOptions(opts []SaveOption) *saveOptions {
+	o := &saveOptions{password: e.encryptPassword}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+/*
+*
+WriteTo write the workbook to w, it returns the count of bytes written
+*/
+func (e *Excel) WriteTo(w io.Writer, opts ...SaveOption) (n int64, err error) {
+	o := e.newSaveOptions(opts)
+	cw := &countWriter{w: w}
+	if !o.compression {
+		_, err = e.file.WriteTo(cw, excelize.Options{Password: o.password})
+		return cw.n, err
+	}
+
+	// the parts are compressed by excelize at the default level, so compress them again
+	var buf bytes.Buffer
+	if _, err = e.file.WriteTo(&buf, excelize.Options{}); err != nil {
+		return 0, err
+	}
+	data, err := recompress(buf.Bytes(), o.compressionLevel)
+	if err != nil {
+		return 0, errors.Wrap(err, "recompress")
+	}
+	if o.password != "" {
+		if data, err = excelize.Encrypt(data, &excelize.Options{Password: o.password}); err != nil {
+			return 0, errors.Wrap(err, "excelize.Encrypt")
+		}
+	}
+	_, err = cw.Write(data)
+	return cw.n, err
+}
+
+/*
+*
+Bytes return the content of the workbook
+*/
+func (e *Excel) Bytes(opts ...SaveOption) ([]byte, error) {
+	var buf bytes.Buffer
+	if _, err := e.WriteTo(&buf, opts...); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+/*
+*
+SaveAs save the workbook to file at path, the extension of path must be a excel workbook one, ex: .xlsx
+the workbook is written to a temp file in the same dir and renamed to path, so an existing file at path is kept if
+saving fails, and the path of workbook is not changed by SaveAs
+*/
+func (e *Excel) SaveAs(path string, opts ...SaveOption) (err error) {
+	if !_workbookExts[strings.ToLower(filepath.Ext(path))] {
+		return excelize.ErrWorkbookFileFormat
+	}
+	mode := os.FileMode(0o644)
+	if info, err := os.Stat(path); err == nil {
+		mode = info.Mode().Perm()
+	}
+
+	f, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = f.Close()
+			_ = os.Remove(f.Name())
+		}
+	}()
+
+	// excelize sets the content type by the extension of path
+	prevPath := e.file.Path
+	e.file.Path = path
+	_, err = e.WriteTo(f, opts...)
+	e.file.Path = prevPath
+	if err != nil {
+		return err
+	}
+	if err = f.Chmod(mode); err != nil {
+		return err
+	}
+	if err = f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), path)
+}
+
+/*
+*
+WriteHTTP write the workbook to the http response as an attachment named filename, ex: "合同.xlsx"
+*/
+func (e *Excel) WriteHTTP(w http.ResponseWriter, filename string, opts ...SaveOption) error {
+	data, err := e.Bytes(opts...)
+	if err != nil {
+		return err
+	}
+
+	w.Header().Set("Content-Type", _xlsxContentType)
+	w.Header().Set("Content-Disposition", ContentDisposition(filename))
+	w.Header().Set("Content-Length", fmt.Sprint(len(data)))
+	_, err = w.Write(data)
+	return err
+}
+
+/*
+*
+Close release the temp files of the workbook
+*/
+func (e *Excel) Close() error {
+	return e.file.Close()
+}
+
+/*
+*
+ContentDisposition return the Content-Disposition header value of an attachment, the filename is encoded by RFC 5987
+in the filename* parameter, ex: 合同.xlsx is encoded as %E5%90%88%E5%90%8C.xlsx, and the non ASCII chars are
+replaced by _ in the filename parameter for the old clients
+*/
+func ContentDisposition(filename string) string {
+	var fallback, encoded strings.Builder
+	for _, r := range filename {
+		switch {
+		case r == '"' || r == '\\' || r < 0x20 || r > 0x7E:
+			fallback.WriteByte('_')
+		default:
+			fallback.WriteRune(r)
+		}
+	}
+	for _, b := range []byte(filename) {
+		if isRFC5987AttrChar(b) {
+			encoded.WriteByte(b)
+			continue
+		}
+		fmt.Fprintf(&encoded, "%%%02X", b)
+	}
+	return fmt.Sprintf(`attachment; filename="%s"; filename*=UTF-8''%s`, fallback.String(), encoded.String())
+}
+
+func isRFC5987AttrChar(b byte) bool {
+	switch {
+	case b >= 'a' && b <= 'z', b >= 'A' && b <= 'Z', b >= '0' && b <= '9':
+		return true
+	}
+	return strings.IndexByte("!#$&+-.^_`|~", b) != -1
+}
+
+/*
+*
+recompress compress the parts of xlsx data at level again
+*/
+func recompress(data []byte, level int) ([]byte, error) {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	zw.RegisterCompressor(zip.Deflate, func(w io.Writer) (io.WriteCloser, error) {
+		return flate.NewWriter(w, level)
+	})
+	method := zip.Deflate
+	if level == flate.NoCompression {
+		method = zip.Store
+	}
+	for _, part := range zr.File {
+		var fw io.Writer
+		if fw, err = zw.CreateHeader(&zip.FileHeader{Name: part.Name, Method: method, Modified: part.Modified}); err != nil {
+			return nil, err
+		}
+		var fr io.ReadCloser
+		if fr, err = part.Open(); err != nil {
+			return nil, err
+		}
+		_, err = io.Copy(fw, fr)
+		_ = fr.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+	if err = zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+/*
+*
+countWriter count the bytes written to w
+*/
+type countWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (cw *countWriter) Write(p []byte) (int, error) {
+	n, err := cw.w.Write(p)
+	cw.n += int64(n)
+	return n, err
+}
@@ -0,0 +1,51 @@
+package excel
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/xuri/excelize/v2"
+)
+
+func TestSaveAs(t *testing.T) {
+	dir := t.TempDir()
+	e := newTestExcel(t, _asyncPaths, [][]interface{}{{"HT-001", 10, 1}})
+	prevPath := e.file.Path
+
+	// an unsupported extension keeps the existing file
+	existing := filepath.Join(dir, "existing.xls")
+	if err := os.WriteFile(existing, []byte("legacy"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := e.SaveAs(existing); !errors.Is(err, excelize.ErrWorkbookFileFormat) {
+		t.Fatalf("expect ErrWorkbookFileFormat, got %v", err)
+	}
+	if data, err := os.ReadFile(existing); err != nil || string(data) != "legacy" {
+		t.Fatalf("the existing file is changed: %q %v", data, err)
+	}
+
+	path := filepath.Join(dir, "saved.xlsx")
+	if err := e.SaveAs(path); err != nil {
+		t.Fatal(err)
+	}
+	if e.file.Path != prevPath {
+		t.Fatalf("the path of workbook is changed to %s", e.file.Path)
+	}
+	saved, err := NewExcelFromFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if value, _ := saved.GetCellValue("A3"); value != "HT-001" {
+		t.Fatalf("unexpected saved value %q", value)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("the temp file is left in %v", entries)
+	}
+}
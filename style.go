@@ -0,0 +1,233 @@
+package excel
+
+import (
+	"reflect"
+	"strconv"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/pkg/errors"
+	"github.com/xuri/excelize/v2"
+)
+
+const (
+	_widthTag  = "exwidth"
+	_numFmtTag = "exnumfmt"
+
+	// _defaultTimeNumFmtId the built-in number format m/d/yy h:mm, which excelize sets to the cells of time values
+	_defaultTimeNumFmtId = 22
+	// the width of auto-fit cols is limited in [_minAutoFitWidth, _maxAutoFitWidth]
+	_minAutoFitWidth = 8
+	_maxAutoFitWidth = 60
+)
+
+var _timeType = reflect.TypeOf(time.Time{})
+
+/*
+*
+exportStyle the style options of export
+*/
+type exportStyle struct {
+	// key is the depth of header, 0 is the top
+	headers    map[int]*excelize.Style
+	wrapText   bool
+	borders    bool
+	zebraColor string
+	autoFit    bool
+	// the number format of time cols without tag exnumfmt
+	timeNumFmt string
+}
+
+/*
+*
+enabled report whether the data cells are styled
+*/
+func (s *exportStyle) enabled() bool {
+	return s != nil && (s.wrapText || s.borders || s.zebraColor != "")
+}
+
+/*
+*
+colNumFmt the number format of a data col, custom is prior to the built-in id, both are zero if the col has no format
+*/
+type colNumFmt struct {
+	id     int
+	custom string
+}
+
+/*
+*
+colStyle the style ids of a data col
+*/
+type colStyle struct {
+	odd, even int
+}
+
+/*
+*
+getHeaderStyleId return the style id of the header cells at depth, the style is created once
+*/
+func (e *Excel) getHeaderStyleId(depth int) (styleId int, err error) {
+	if e.style == nil || (e.style.headers[depth] == nil && !e.style.borders) {
+		return e.fieldStyleId, nil
+	}
+	if styleId, ok := e.headerStyleIds[depth]; ok {
+		return styleId, nil
+	}
+
+	style := e.style.headers[depth]
+	if style == nil {
+		style, err = e.file.GetStyle(e.fieldStyleId)
+		if err != nil {
+			return 0, errors.Wrap(err, "e.file.GetStyle")
+		}
+	}
+	if e.style.borders && len(style.Border) == 0 {
+		copied := *style
+		copied.Border = thinBorders()
+		style = &copied
+	}
+	if styleId, err = e.newStyle(style); err != nil {
+		return 0, errors.Wrap(err, "e.newStyle")
+	}
+	if e.headerStyleIds == nil {
+		e.headerStyleIds = make(map[int]int)
+	}
+	e.headerStyleIds[depth] = styleId
+	return
+}
+
+/*
+*
+getColStyles return the styles of data cols by the fields of typ, nil if the data cells are not styled
+the number format of a col is set by tag exnumfmt, ex: exnumfmt:"#,##0.00", the time cols are formatted by option
+TimeNumFmt, or keep the date time format of excelize if they are styled by the other options
+*/
+func (e *Excel) getColStyles(typ reflect.Type) (styles []colStyle, err error) {
+	fields := exportFields(typ)
+	numFmts := make([]colNumFmt, len(fields))
+	var hasNumFmt bool
+	for j, field := range fields {
+		numFmts[j].custom = field.numFmt
+		if numFmts[j].custom == "" && isTimeType(typ.Field(field.index).Type) {
+			if e.style != nil && e.style.timeNumFmt != "" {
+				numFmts[j].custom = e.style.timeNumFmt
+			} else {
+				// the style of the col replaces the format which excelize sets to the time cells
+				numFmts[j].id = _defaultTimeNumFmtId
+			}
+		}
+		hasNumFmt = hasNumFmt || numFmts[j].custom != ""
+	}
+	if !e.style.enabled() && !hasNumFmt {
+		return nil, nil
+	}
+
+	// the cols of same number format share the styles
+	cache := make(map[colNumFmt]colStyle)
+	styles = make([]colStyle, len(fields))
+	for j := range fields {
+		if s, ok := cache[numFmts[j]]; ok {
+			styles[j] = s
+			continue
+		}
+
+		style := &excelize.Style{NumFmt: numFmts[j].id}
+		if numFmts[j].custom != "" {
+			style.CustomNumFmt = &numFmts[j].custom
+		}
+		if e.style != nil && e.style.wrapText {
+			style.Alignment = &excelize.Alignment{Vertical: "center", WrapText: true}
+		}
+		if e.style != nil && e.style.borders {
+			style.Border = thinBorders()
+		}
+		var s colStyle
+		if s.odd, err = e.newStyle(style); err != nil {
+			return nil, errors.Wrap(err, "e.newStyle")
+		}
+		s.even = s.odd
+		if e.style != nil && e.style.zebraColor != "" {
+			style.Fill = excelize.Fill{Type: "pattern", Pattern: 1, Color: []string{e.style.zebraColor}}
+			if s.even, err = e.newStyle(style); err != nil {
+				return nil, errors.Wrap(err, "e.newStyle")
+			}
+		}
+		cache[numFmts[j]] = s
+		styles[j] = s
+	}
+	return
+}
+
+func isTimeType(typ reflect.Type) bool {
+	return typ == _timeType || typ == reflect.TypeOf(TimeField{})
+}
+
+func thinBorders() []excelize.Border {
+	borders := make([]excelize.Border, 0, 4)
+	for _, typ := range []string{"left", "top", "right", "bottom"} {
+		borders = append(borders, excelize.Border{Type: typ, Color: "000000", Style: 1})
+	}
+	return borders
+}
+
+/*
+*
+setColWidths set the widths of data cols, the width is set by tag exwidth, ex: exwidth:"20",
+or fitted to the header and values of the col if option AutoFitColWidth is set
+*/
+func (e *Excel) setColWidths(sheet string, typ reflect.Type, rows []interface{}) (err error) {
+	autoFit := e.style != nil && e.style.autoFit
+	for j, field := range exportFields(typ) {
+		width := field.width
+		if width == 0 && autoFit {
+			width = float64(displayWidth(field.path[len(field.path)-1]))
+			for _, row := range rows {
+				v := reflect.Indirect(reflect.ValueOf(row).Elem())
+				width = max(width, float64(displayWidth(formatCSVValue(exportValue(v.Field(field.index))))))
+			}
+			width = min(max(width+2, _minAutoFitWidth), _maxAutoFitWidth)
+		}
+		if width == 0 {
+			continue
+		}
+
+		var colName string
+		if colName, err = excelize.ColumnNumberToName(j + 1); err != nil {
+			return err
+		}
+		if err = e.file.SetColWidth(sheet, colName, colName, width); err != nil {
+			return errors.Wrap(err, "e.file.SetColWidth")
+		}
+	}
+	return
+}
+
+/*
+*
+displayWidth return the width of s in excel, a wide char (ex: chinese) takes 2
+*/
+func displayWidth(s string) (width int) {
+	for _, r := range s {
+		if r >= utf8.RuneSelf && (unicode.Is(unicode.Han, r) || unicode.In(r, unicode.Hangul, unicode.Hiragana, unicode.Katakana) ||
+			(r >= 0xFF01 && r <= 0xFF60) || (r >= 0x3000 && r <= 0x303F)) {
+			width += 2
+			continue
+		}
+		width++
+	}
+	return
+}
+
+/*
+*
+parseWidthTag parse the tag exwidth, 0 if it's not set or invalid
+*/
+func parseWidthTag(tag string) float64 {
+	width, err := strconv.ParseFloat(tag, 64)
+	if err != nil || width < 0 {
+		return 0
+	}
+	return width
+}
@@ -0,0 +1,165 @@
+package excel
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/xuri/excelize/v2"
+)
+
+/*
+*
+StylePatch the parts of style to apply to cells, the nil parts are kept from the current style of cells
+*/
+type StylePatch struct {
+	Fill         *excelize.Fill
+	Font         *excelize.Font
+	Border       []excelize.Border
+	Alignment    *excelize.Alignment
+	Protection   *excelize.Protection
+	NumFmt       *int
+	CustomNumFmt *string
+	// Replace replace the current style of cells with the patch instead of merging them
+	Replace bool
+}
+
+/*
+*
+styleCache the styles created in the file, so that the same style is created only once
+*/
+type styleCache struct {
+	// key is the json of style, value is the style id
+	styles map[string]int
+	// key is the current style id and the json of patch, value is the patched style id
+	patched map[patchedStyleKey]int
+}
+
+type patchedStyleKey struct {
+	styleId int
+	patch   string
+}
+
+/*
+*
+newStyle return the id of style, the style is created only once for the same definition
+*/
+func (e *Excel) newStyle(style *excelize.Style) (int, error) {
+	e.styleMu.Lock()
+	defer e.styleMu.Unlock()
+	return e.newStyleLocked(style)
+}
+
+func (e *Excel) newStyleLocked(style *excelize.Style) (int, error) {
+	if e.styles.styles == nil {
+		e.styles.styles = make(map[string]int)
+		e.styles.patched = make(map[patchedStyleKey]int)
+	}
+	b, err := json.Marshal(style)
+	if err != nil {
+		return 0, err
+	}
+	if styleId, ok := e.styles.styles[string(b)]; ok {
+		return styleId, nil
+	}
+
+	styleId, err := e.file.NewStyle(style)
+	if err != nil {
+		return 0, err
+	}
+	e.styles.styles[string(b)] = styleId
+	return styleId, nil
+}
+
+/*
+*
+patchStyle return the id of the style which is styleId patched by patch
+*/
+func (e *Excel) patchStyle(styleId int, patch *StylePatch, patchKey string) (int, error) {
+	e.styleMu.Lock()
+	defer e.styleMu.Unlock()
+
+	key := patchedStyleKey{styleId: styleId, patch: patchKey}
+	if patched, ok := e.styles.patched[key]; ok {
+		return patched, nil
+	}
+
+	style := new(excelize.Style)
+	if !patch.Replace {
+		var err error
+		if style, err = e.file.GetStyle(styleId); err != nil {
+			return 0, errors.Wrap(err, "e.file.GetStyle")
+		}
+	}
+	if patch.Fill != nil {
+		style.Fill = *patch.Fill
+	}
+	if patch.Font != nil {
+		style.Font = patch.Font
+	}
+	if patch.Border != nil {
+		style.Border = patch.Border
+	}
+	if patch.Alignment != nil {
+		style.Alignment = patch.Alignment
+	}
+	if patch.Protection != nil {
+		style.Protection = patch.Protection
+	}
+	if patch.NumFmt != nil {
+		style.NumFmt = *patch.NumFmt
+	}
+	if patch.CustomNumFmt != nil {
+		style.CustomNumFmt = patch.CustomNumFmt
+	}
+
+	patched, err := e.newStyleLocked(style)
+	if err != nil {
+		return 0, errors.Wrap(err, "e.newStyle")
+	}
+	e.styles.patched[key] = patched
+	return patched, nil
+}
+
+/*
+*
+ApplyStyle apply patch to the cells of sheet in rangeRef, ex: "A1" or "A1:C10", the patch is merged into the current
+style of every cell unless patch.Replace is true, the styles are cached, so applying the same patch to many cells
+creates only one style for every distinct current style
+*/
+func (e *Excel) ApplyStyle(sheet, rangeRef string, patch StylePatch) (err error) {
+	b, err := json.Marshal(patch)
+	if err != nil {
+		return err
+	}
+
+	hCell, vCell, _ := strings.Cut(rangeRef, ":")
+	if vCell == "" {
+		vCell = hCell
+	}
+	startCol, startRow, err := excelize.CellNameToCoordinates(hCell)
+	if err != nil {
+		return err
+	}
+	endCol, endRow, err := excelize.CellNameToCoordinates(vCell)
+	if err != nil {
+		return err
+	}
+
+	for row := min(startRow, endRow); row <= max(startRow, endRow); row++ {
+		for col := min(startCol, endCol); col <= max(startCol, endCol); col++ {
+			axis, _ := excelize.CoordinatesToCellName(col, row)
+			var styleId int
+			if styleId, err = e.file.GetCellStyle(sheet, axis); err != nil {
+				return errors.Wrap(err, "e.file.GetCellStyle")
+			}
+			if styleId, err = e.patchStyle(styleId, &patch, string(b)); err != nil {
+				return err
+			}
+			if err = e.file.SetCellStyle(sheet, axis, axis, styleId); err != nil {
+				return errors.Wrap(err, "e.file.SetCellStyle")
+			}
+		}
+	}
+	return
+}
@@ -0,0 +1,62 @@
+package excel
+
+import (
+	"testing"
+	"time"
+
+	"github.com/xuri/excelize/v2"
+)
+
+type styleRow struct {
+	Number StringField `ex:"合同|编号"`
+	Begin  TimeField   `ex:"合同|开始时间"`
+}
+
+func exportTimeCell(t *testing.T, begin time.Time, options ...Option) (value string, numFmt int) {
+	t.Helper()
+	e, err := NewExcelFromData([]interface{}{&styleRow{Number: NewStringField("HT-001"), Begin: NewTimeField(begin)}}, options...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sheet := e.activeSheetNames[0]
+	value, _ = e.file.GetCellValue(sheet, "B3")
+	styleId, _ := e.file.GetCellStyle(sheet, "B3")
+	style, err := e.file.GetStyle(styleId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return value, style.NumFmt
+}
+
+func TestExportTimeColNumFmt(t *testing.T) {
+	begin := time.Date(2024, 1, 2, 15, 4, 0, 0, time.UTC)
+
+	// the cell written by excelize as before the styles of export
+	f := excelize.NewFile()
+	if err := f.SetCellValue("Sheet1", "A1", begin); err != nil {
+		t.Fatal(err)
+	}
+	prevValue, _ := f.GetCellValue("Sheet1", "A1")
+
+	if value, numFmt := exportTimeCell(t, begin); value != prevValue || numFmt != _defaultTimeNumFmtId {
+		t.Fatalf("expect the time cell %q of format %d without options, got %q of %d", prevValue, _defaultTimeNumFmtId, value, numFmt)
+	}
+	// the other styles keep the date time format
+	if value, numFmt := exportTimeCell(t, begin, Borders(true)); value != prevValue || numFmt != _defaultTimeNumFmtId {
+		t.Fatalf("expect the time cell %q of format %d with borders, got %q of %d", prevValue, _defaultTimeNumFmtId, value, numFmt)
+	}
+	if value, _ := exportTimeCell(t, begin, TimeNumFmt("yyyy-mm-dd")); value != "2024-01-02" {
+		t.Fatalf("expect the time col formatted as yyyy-mm-dd, got %q", value)
+	}
+}
+
+func TestExportLeafHeaderDefaultStyle(t *testing.T) {
+	e, err := NewExcelFromData([]interface{}{&styleRow{Number: NewStringField("HT-001")}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the leaf header cells keep the default style without header style options
+	if styleId, _ := e.file.GetCellStyle(e.activeSheetNames[0], "A2"); styleId != 0 {
+		t.Fatalf("expect the default style of leaf header, got %d", styleId)
+	}
+}
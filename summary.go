@@ -0,0 +1,136 @@
+package excel
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/pkg/errors"
+	"github.com/xuri/excelize/v2"
+)
+
+const (
+	_defaultSummaryLabel = "合计"
+)
+
+type AggregateFunc int
+
+const (
+	AggregateSum AggregateFunc = iota + 1
+	// AggregateCount count the non-empty cells
+	AggregateCount
+	AggregateAvg
+	AggregateMin
+	AggregateMax
+)
+
+/*
+*
+subtotalFunc return the function number of SUBTOTAL
+*/
+func (f AggregateFunc) subtotalFunc() int {
+	switch f {
+	case AggregateSum:
+		return 9
+	case AggregateCount:
+		return 3
+	case AggregateAvg:
+		return 1
+	case AggregateMin:
+		return 5
+	case AggregateMax:
+		return 4
+	default:
+		return 0
+	}
+}
+
+/*
+*
+Aggregate aggregate the exported col of ex path by Func
+*/
+type Aggregate struct {
+	Path string
+	Func AggregateFunc
+}
+
+/*
+*
+summary the summary row of option WithSummary
+*/
+type summary struct {
+	label      string
+	aggregates []Aggregate
+}
+
+/*
+*
+writeSummaries write the summary row below the data rows of every sheet, the data rows of every sheet begin at dataRow
+and end at lastRows[idx]
+*/
+func (e *Excel) writeSummaries(typ reflect.Type, lastRows []int, dataRow int) (err error) {
+	if e.summary == nil {
+		return
+	}
+
+	for idx, lastRow := range lastRows {
+		if lastRow < dataRow {
+			continue
+		}
+		sheet := e.activeSheetNames[idx]
+		if err = e.writeSummaryRow(sheet, typ, e.summary.label, lastRow+1, dataRow, lastRow); err != nil {
+			return err
+		}
+	}
+	return
+}
+
+/*
+*
+writeSummaryRow write the label and SUBTOTAL formulas of the aggregates at row, the formulas aggregate the rows
+from firstRow to lastRow, the label is written at the first col which is not aggregated.
+The subtotals in the rows are ignored by SUBTOTAL, so the summary of all groups doesn't count the group subtotals
+*/
+func (e *Excel) writeSummaryRow(sheet string, typ reflect.Type, label string, row, firstRow, lastRow int) (err error) {
+	fields := exportFields(typ)
+	aggregated := make(map[int]bool)
+	for _, aggregate := range e.summary.aggregates {
+		col := fieldColIndex(fields, aggregate.Path)
+		if col == -1 {
+			return errors.Errorf("no field of ex path %s", aggregate.Path)
+		}
+		if aggregate.Func.subtotalFunc() == 0 {
+			return errors.Errorf("aggregate func %d of ex path %s is invalid", aggregate.Func, aggregate.Path)
+		}
+		aggregated[col] = true
+
+		var colName, axis string
+		if colName, err = excelize.ColumnNumberToName(col); err != nil {
+			return err
+		}
+		if axis, err = excelize.CoordinatesToCellName(col, row); err != nil {
+			return err
+		}
+		formula := fmt.Sprintf("SUBTOTAL(%d,%s%d:%s%d)", aggregate.Func.subtotalFunc(), colName, firstRow, colName, lastRow)
+		if err = e.file.SetCellFormula(sheet, axis, formula); err != nil {
+			return errors.Wrap(err, "e.file.SetCellFormula")
+		}
+	}
+
+	for col := 1; col <= len(fields); col++ {
+		if aggregated[col] {
+			continue
+		}
+		var axis string
+		if axis, err = excelize.CoordinatesToCellName(col, row); err != nil {
+			return err
+		}
+		if err = e.file.SetCellValue(sheet, axis, label); err != nil {
+			return errors.Wrap(err, "e.file.SetCellValue")
+		}
+		break
+	}
+
+	hCell, _ := excelize.CoordinatesToCellName(1, row)
+	vCell, _ := excelize.CoordinatesToCellName(len(fields), row)
+	return e.ApplyStyle(sheet, hCell+":"+vCell, StylePatch{Font: &excelize.Font{Bold: true}})
+}
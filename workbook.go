@@ -0,0 +1,148 @@
+package excel
+
+import (
+	"path"
+	"reflect"
+
+	"github.com/pkg/errors"
+)
+
+/*
+*
+SheetSchema describe how the sheets matched by Name are scanned to structs
+*/
+type SheetSchema struct {
+	// Name is the sheet name or a sheet name pattern in path.Match syntax, ex: "付款计划*"
+	Name string
+	// HeaderRow is the same as option HeaderRow, 0 means the headers are read from merge cells
+	HeaderRow int
+	// DetectHeaderRows detect the header in the first DetectHeaderRows rows by the ex paths of Resps, it's prior to HeaderRow
+	DetectHeaderRows int
+	// Required make ScanWorkbook return error if no sheet matches Name
+	Required bool
+	// Resps are struct pointers, every data row of the sheet is scanned into new values of their types
+	Resps []interface{}
+}
+
+/*
+*
+SheetData the scanned data of a sheet
+*/
+type SheetData struct {
+	// Sheet is the name of the scanned sheet
+	Sheet string
+	// Schema is the schema which the sheet matched
+	Schema *SheetSchema
+	// RowIndices are the excel row indices (begin from 1) of the scanned rows
+	RowIndices []int
+	// Slices hold a typed slice for each of Schema.Resps, ex: []*BaseInfo for &BaseInfo{}
+	Slices []interface{}
+}
+
+/*
+*
+Rows return the typed slice scanned for resp, resp must be one of the Schema.Resps types
+ex: data.Rows(&BaseInfo{}).([]*BaseInfo)
+*/
+func (d *SheetData) Rows(resp interface{}) interface{} {
+	typ := reflect.SliceOf(reflect.TypeOf(resp))
+	for _, slice := range d.Slices {
+		if reflect.TypeOf(slice) == typ {
+			return slice
+		}
+	}
+	return nil
+}
+
+/*
+*
+ScanWorkbook scan every sheet matched by schemas into typed slices
+a sheet is scanned by the first schema it matches
+after all sheets are scanned, child rows are assembled into the parent's exref slice fields,
+if some rows can't be linked, the scanned data is returned with RefErrors
+*/
+func (e *Excel) ScanWorkbook(schemas ...*SheetSchema) (res []*SheetData, err error) {
+	sheetNames := e.file.GetSheetList()
+	scanned := make(map[string]bool, len(sheetNames))
+	for _, schema := range schemas {
+		var matched bool
+		for _, sheetName := range sheetNames {
+			var ok bool
+			if ok, err = path.Match(schema.Name, sheetName); err != nil {
+				err = errors.Wrapf(err, "path.Match pattern:(%s)", schema.Name)
+				return
+			}
+			if !ok {
+				continue
+			}
+			matched = true
+			if scanned[sheetName] {
+				continue
+			}
+			scanned[sheetName] = true
+
+			var data *SheetData
+			if data, err = e.scanSheet(sheetName, schema); err != nil {
+				return
+			}
+			res = append(res, data)
+		}
+
+		if !matched && schema.Required {
+			err = errors.Errorf("required sheet %s doesn't exist", schema.Name)
+			return
+		}
+	}
+
+	err = linkSheets(res)
+	return
+}
+
+func (e *Excel) scanSheet(sheetName string, schema *SheetSchema) (data *SheetData, err error) {
+	var detect *headerDetect
+	if schema.DetectHeaderRows > 0 {
+		detect = newHeaderDetect(schema.DetectHeaderRows, schema.Resps...)
+	}
+	importer, err := e.buildSheetImporter(sheetName, schema.HeaderRow, detect)
+	if err != nil {
+		err = errors.Wrapf(err, "e.buildSheetImporter sheet:(%s)", sheetName)
+		return
+	}
+
+	rows, err := e.getSheetDataRows(sheetName, importer.getRowsBeginIndex())
+	if err != nil {
+		err = errors.Wrapf(err, "e.getSheetDataRows sheet:(%s)", sheetName)
+		return
+	}
+
+	data = &SheetData{Sheet: sheetName, Schema: schema}
+	slices := make([]reflect.Value, len(schema.Resps))
+	for i, resp := range schema.Resps {
+		slices[i] = reflect.MakeSlice(reflect.SliceOf(reflect.TypeOf(resp)), 0, len(rows))
+	}
+
+	for i := importer.getRowsBeginIndex(); i < len(rows); i++ {
+		if len(rows[i]) == 0 {
+			continue
+		}
+
+		resps := make([]interface{}, len(schema.Resps))
+		for j, resp := range schema.Resps {
+			resps[j] = reflect.New(reflect.TypeOf(resp).Elem()).Interface()
+		}
+		if _, err = importer.ScanExRow(rows[i], resps...); err != nil {
+			err = errors.Wrapf(err, "sheet:(%s) row:(%d)", sheetName, i+1)
+			return
+		}
+
+		for j, resp := range resps {
+			slices[j] = reflect.Append(slices[j], reflect.ValueOf(resp))
+		}
+		data.RowIndices = append(data.RowIndices, i+1)
+	}
+
+	for _, slice := range slices {
+		data.Slices = append(data.Slices, slice.Interface())
+	}
+	return
+}
@@ -0,0 +1,175 @@
+package excel
+
+import (
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/xuri/excelize/v2"
+)
+
+/*
+*
+WriteExRow write the fields of resps back to the row (begin from 1) of sheet, mirroring ScanExRow,
+the col of a field is located by its ex path in the importer tree, the fields which match no header are skipped.
+The styles of cells are kept, so the user's own template keeps its formatting
+Note: resps must be struct pointer types
+*/
+func (e *Excel) WriteExRow(sheet string, rowIndex int, resps ...interface{}) (err error) {
+	root := e.SheetImporter(sheet)
+	if root == nil {
+		return errors.Errorf("sheet %s is not active", sheet)
+	}
+	if rowIndex <= root.getRowsBeginIndex() {
+		return errors.Errorf("row %d is in the header", rowIndex)
+	}
+	if len(root.leafNodes) == 0 {
+		root.leafNodes = root.getLeafNodes()
+	}
+
+	for _, resp := range resps {
+		v := reflect.Indirect(reflect.ValueOf(resp).Elem())
+		for _, fieldPlan := range root.getScanPlan(v.Type(), false).fields {
+			leafNode := root.leafNodes[fieldPlan.leafIndex]
+			var axis string
+			axis, err = excelize.CoordinatesToCellName(leafNode.colIndexStart, rowIndex)
+			if err != nil {
+				return errors.Wrap(err, "excelize.CoordinatesToCellName")
+			}
+			err = e.file.SetCellValue(sheet, axis, writeBackValue(exportValue(v.Field(fieldPlan.fieldIndex))))
+			if err != nil {
+				return errors.Wrap(err, "e.file.SetCellValue")
+			}
+		}
+	}
+	return
+}
+
+/*
+*
+writeBackValue return the cell value which the field can translate back, the numbers are kept as numbers,
+the times and bools are written as the text which TimeField and BoolField read
+*/
+func writeBackValue(value interface{}) interface{} {
+	switch value.(type) {
+	case time.Time, bool:
+		return formatCSVValue(value)
+	default:
+		return value
+	}
+}
+
+/*
+*
+AppendHeaders append the header group of paths after the last col of every active sheet, see AppendSheetHeaders
+*/
+func (e *Excel) AppendHeaders(paths ...string) error {
+	for _, sheet := range e.activeSheetNames {
+		if err := e.AppendSheetHeaders(sheet, paths...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+/*
+*
+AppendSheetHeaders append the header group of paths after the last col of sheet, ex: "处理结果|状态", "处理结果|原因".
+The headers are merge cells in the existing header rows, a leaf header spans down to the last header row.
+The importer tree of sheet is extended, so SubImporter("处理结果") resolves and WriteExRow fills the new cols
+*/
+func (e *Excel) AppendSheetHeaders(sheet string, paths ...string) (err error) {
+	root := e.SheetImporter(sheet)
+	if root == nil {
+		return errors.Errorf("sheet %s is not active", sheet)
+	}
+	if len(paths) == 0 || len(root.childImporters) == 0 {
+		return errors.New("no header to append")
+	}
+
+	splitPaths := make([][]string, 0, len(paths))
+	for _, path := range paths {
+		splitPaths = append(splitPaths, strings.Split(path, "|"))
+	}
+	pathDepth := len(splitPaths[0])
+	for _, path := range splitPaths {
+		if len(path) != pathDepth {
+			return errors.New("path depth is not same")
+		}
+	}
+	rowIndexStart, rowIndexEnd := root.childImporters[0].rowIndexStart, root.getRowsBeginIndex()
+	if pathDepth > rowIndexEnd-rowIndexStart+1 {
+		return errors.Errorf("path depth %d is larger than header rows %d", pathDepth, rowIndexEnd-rowIndexStart+1)
+	}
+
+	lastColIndex, err := e.getSheetLastColIndex(sheet)
+	if err != nil {
+		return errors.Wrap(err, "e.getSheetLastColIndex")
+	}
+	lastColIndex = max(lastColIndex, root.getLeafNodes()[len(root.getLeafNodes())-1].colIndexEnd)
+
+	col := lastColIndex + 1
+	for _, h := range getHeadersFromPaths(splitPaths, 0, pathDepth) {
+		var node *Importer
+		if node, err = e.appendHeader(sheet, root, h, col, rowIndexStart, rowIndexEnd); err != nil {
+			return err
+		}
+		root.childImporters = append(root.childImporters, node)
+		col = node.colIndexEnd + 1
+	}
+	root.colIndexEnd = max(root.colIndexEnd, col-1)
+
+	// the leaf nodes are changed
+	root.leafNodes = nil
+	root.resetScanPlans()
+	return
+}
+
+/*
+*
+appendHeader write header h at col and row of sheet as merge cell, and build the importer node of it under parent
+*/
+func (e *Excel) appendHeader(sheet string, parent *Importer, h *header, col, row, rowIndexEnd int) (node *Importer, err error) {
+	node = &Importer{value: h.title, colIndexStart: col, rowIndexStart: row, rowIndexEnd: row}
+	node.inherit(parent)
+	node.path = append(append([]string{}, parent.path...), h.title)
+
+	childCol := col
+	for _, child := range h.children {
+		var childNode *Importer
+		if childNode, err = e.appendHeader(sheet, node, child, childCol, row+1, rowIndexEnd); err != nil {
+			return nil, err
+		}
+		node.childImporters = append(node.childImporters, childNode)
+		childCol = childNode.colIndexEnd + 1
+	}
+	node.colIndexEnd = max(col, childCol-1)
+	if len(h.children) == 0 {
+		// the leaf spans down to the last header row
+		node.rowIndexEnd = rowIndexEnd
+	}
+
+	hCell, err := excelize.CoordinatesToCellName(node.colIndexStart, node.rowIndexStart)
+	if err != nil {
+		return nil, errors.Wrap(err, "excelize.CoordinatesToCellName")
+	}
+	vCell, err := excelize.CoordinatesToCellName(node.colIndexEnd, node.rowIndexEnd)
+	if err != nil {
+		return nil, errors.Wrap(err, "excelize.CoordinatesToCellName")
+	}
+	if err = e.file.SetCellValue(sheet, hCell, h.title); err != nil {
+		return nil, errors.Wrap(err, "e.file.SetCellValue")
+	}
+	if err = e.file.MergeCell(sheet, hCell, vCell); err != nil {
+		return nil, errors.Wrap(err, "e.file.MergeCell")
+	}
+	styleId, err := e.getHeaderStyleId(len(node.path) - 1)
+	if err != nil {
+		return nil, err
+	}
+	if err = e.file.SetCellStyle(sheet, hCell, vCell, styleId); err != nil {
+		return nil, errors.Wrap(err, "e.file.SetCellStyle")
+	}
+	return
+}
@@ -0,0 +1,60 @@
+package excel
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+type processResult struct {
+	Status StringField `ex:"处理结果|状态"`
+	Reason StringField `ex:"处理结果|原因"`
+}
+
+func TestAppendSheetHeadersReopen(t *testing.T) {
+	e := newTestExcel(t, _asyncPaths, [][]interface{}{{"HT-001", 10, 1}})
+	sheet := e.activeSheetNames[0]
+	if err := e.AppendSheetHeaders(sheet, "处理结果|状态", "处理结果|原因"); err != nil {
+		t.Fatal(err)
+	}
+	result := &processResult{Status: NewStringField("失败"), Reason: NewStringField("金额不符")}
+	if err := e.WriteExRow(sheet, 3, result); err != nil {
+		t.Fatal(err)
+	}
+
+	// the merge cells of appended headers are saved after the existing ones
+	path := filepath.Join(t.TempDir(), "result.xlsx")
+	if err := e.SaveAs(path); err != nil {
+		t.Fatal(err)
+	}
+	reopened, err := NewExcelFromFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sub := reopened.SubImporter("处理结果")
+	if sub == nil {
+		t.Fatal("expect the appended header group in the reopened file")
+	}
+	rows, err := reopened.GetRowsWithoutHeader()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 1 {
+		t.Fatalf("expect 1 data row, got %q", rows)
+	}
+
+	got := &processResult{}
+	if _, err = sub.ScanExRow(rows[0], got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Status.GetValue() != "失败" || got.Reason.GetValue() != "金额不符" {
+		t.Fatalf("unexpected scanned result %+v", got)
+	}
+	row := &asyncRow{}
+	if _, err = reopened.ScanExRow(rows[0], row); err != nil {
+		t.Fatal(err)
+	}
+	if row.Number.GetValue() != "HT-001" {
+		t.Fatalf("unexpected scanned row %+v", row)
+	}
+}
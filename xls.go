@@ -0,0 +1,646 @@
+package excel
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"io"
+	"math"
+	"os"
+	"strings"
+	"time"
+	"unicode/utf16"
+
+	"github.com/pkg/errors"
+	"github.com/richardlehane/mscfb"
+	"github.com/xuri/excelize/v2"
+)
+
+// BIFF8 record types
+const (
+	_xlsRecordBOF         = 0x0809
+	_xlsRecordEOF         = 0x000A
+	_xlsRecordFilePass    = 0x002F
+	_xlsRecordDateMode    = 0x0022
+	_xlsRecordBoundSheet  = 0x0085
+	_xlsRecordSST         = 0x00FC
+	_xlsRecordContinue    = 0x003C
+	_xlsRecordFormat      = 0x041E
+	_xlsRecordXF          = 0x00E0
+	_xlsRecordLabelSST    = 0x00FD
+	_xlsRecordLabel       = 0x0204
+	_xlsRecordNumber      = 0x0203
+	_xlsRecordRK          = 0x027E
+	_xlsRecordMulRK       = 0x00BD
+	_xlsRecordBoolErr     = 0x0205
+	_xlsRecordFormula     = 0x0006
+	_xlsRecordString      = 0x0207
+	_xlsRecordMergedCells = 0x00E5
+
+	_xlsBIFF8Version   = 0x0600
+	_xlsSheetTypeSheet = 0
+)
+
+var _oleSignature = []byte{0xD0, 0xCF, 0x11, 0xE0, 0xA1, 0xB1, 0x1A, 0xE1}
+
+type xlsRecord struct {
+	typ  uint16
+	data []byte
+}
+
+type xlsBoundSheet struct {
+	offset uint32
+	typ    byte
+	name   string
+}
+
+/*
+*
+xlsWorkbook the parsed BIFF8 workbook stream
+*/
+type xlsWorkbook struct {
+	stream      []byte
+	date1904    bool
+	sst         []string
+	formats     map[uint16]string
+	xfFormats   []uint16
+	boundSheets []xlsBoundSheet
+}
+
+/*
+*
+xlsWorkbookStream return the workbook stream if r is a legacy xls file, nil otherwise
+*/
+func xlsWorkbookStream(r io.ReaderAt) ([]byte, error) {
+	if !isOLE(r) {
+		return nil, nil
+	}
+	doc, err := mscfb.New(r)
+	if err != nil {
+		return nil, nil
+	}
+	for entry, err := doc.Next(); err == nil; entry, err = doc.Next() {
+		if entry.Name != "Workbook" && entry.Name != "Book" {
+			continue
+		}
+		stream := make([]byte, entry.Size)
+		if _, err = io.ReadFull(entry, stream); err != nil {
+			return nil, errors.Wrap(err, "read workbook stream")
+		}
+		return stream, nil
+	}
+	return nil, nil
+}
+
+/*
+*
+isOLE report whether r begins with the signature of compound file, only the signature is read
+*/
+func isOLE(r io.ReaderAt) bool {
+	header := make([]byte, len(_oleSignature))
+	if _, err := r.ReadAt(header, 0); err != nil {
+		return false
+	}
+	return bytes.Equal(header, _oleSignature)
+}
+
+/*
+*
+openExcelFile open xlsx file, or legacy xls file detected by its signature
+*/
+func openExcelFile(file, password string) (*excelize.File, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	stream, err := xlsWorkbookStream(f)
+	_ = f.Close()
+	if err != nil {
+		return nil, err
+	}
+	if stream != nil {
+		return openXLS(stream)
+	}
+	// keep the path of xlsx file, so that it can be saved
+	return excelize.OpenFile(file, excelize.Options{Password: password})
+}
+
+/*
+*
+openExcelReader open xlsx from reader, or legacy xls detected by its signature
+*/
+func openExcelReader(reader io.Reader, password string) (*excelize.File, error) {
+	br := bufio.NewReader(reader)
+	if header, _ := br.Peek(len(_oleSignature)); !bytes.Equal(header, _oleSignature) {
+		return excelize.OpenReader(br, excelize.Options{Password: password})
+	}
+
+	data, err := io.ReadAll(br)
+	if err != nil {
+		return nil, err
+	}
+	stream, err := xlsWorkbookStream(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	if stream != nil {
+		return openXLS(stream)
+	}
+	return excelize.OpenReader(bytes.NewReader(data), excelize.Options{Password: password})
+}
+
+/*
+*
+openXLS convert a legacy xls (BIFF8) workbook stream into excelize file, the cell values and merge cells are kept
+*/
+func openXLS(stream []byte) (f *excelize.File, err error) {
+	wb := &xlsWorkbook{stream: stream, formats: make(map[uint16]string)}
+	if err = wb.parseGlobals(); err != nil {
+		return nil, errors.Wrap(err, "parse xls globals")
+	}
+
+	f = excelize.NewFile()
+	var sheetCount int
+	for _, boundSheet := range wb.boundSheets {
+		if boundSheet.typ != _xlsSheetTypeSheet {
+			continue
+		}
+		if sheetCount == 0 {
+			err = f.SetSheetName("Sheet1", boundSheet.name)
+		} else {
+			_, err = f.NewSheet(boundSheet.name)
+		}
+		if err != nil {
+			return nil, err
+		}
+		sheetCount++
+
+		if err = wb.parseSheet(f, boundSheet); err != nil {
+			return nil, errors.Wrapf(err, "parse xls sheet:(%s)", boundSheet.name)
+		}
+	}
+	if sheetCount == 0 {
+		return nil, errors.New("no sheet exist in xls")
+	}
+	return
+}
+
+/*
+*
+readRecords read records from offset until the EOF record, the CONTINUE records are kept
+*/
+func (wb *xlsWorkbook) readRecords(offset int, fn func(r *xlsRecord) error) error {
+	for offset+4 <= len(wb.stream) {
+		r := &xlsRecord{typ: binary.LittleEndian.Uint16(wb.stream[offset:])}
+		size := int(binary.LittleEndian.Uint16(wb.stream[offset+2:]))
+		offset += 4
+		if offset+size > len(wb.stream) {
+			return errors.New("xls record is truncated")
+		}
+		r.data = wb.stream[offset : offset+size]
+		offset += size
+
+		if err := fn(r); err != nil {
+			return err
+		}
+		if r.typ == _xlsRecordEOF {
+			return nil
+		}
+	}
+	return errors.New("xls EOF record is missing")
+}
+
+func (wb *xlsWorkbook) parseGlobals() error {
+	// segments of SST record and its CONTINUE records
+	var sstSegments [][]byte
+	var lastType uint16
+	err := wb.readRecords(0, func(r *xlsRecord) error {
+		if r.typ != _xlsRecordContinue {
+			lastType = r.typ
+		}
+		switch r.typ {
+		case _xlsRecordBOF:
+			if len(r.data) < 2 || binary.LittleEndian.Uint16(r.data) != _xlsBIFF8Version {
+				return errors.New("only BIFF8 xls is supported")
+			}
+		case _xlsRecordFilePass:
+			return errors.New("encrypted xls is not supported")
+		case _xlsRecordDateMode:
+			wb.date1904 = len(r.data) >= 2 && binary.LittleEndian.Uint16(r.data) == 1
+		case _xlsRecordBoundSheet:
+			if len(r.data) < 8 {
+				return errors.New("xls BOUNDSHEET record is invalid")
+			}
+			name, _ := readXLSShortString(r.data[6:])
+			wb.boundSheets = append(wb.boundSheets, xlsBoundSheet{
+				offset: binary.LittleEndian.Uint32(r.data),
+				typ:    r.data[5],
+				name:   name,
+			})
+		case _xlsRecordFormat:
+			if len(r.data) < 2 {
+				return nil
+			}
+			format, _ := readXLSString(r.data[2:])
+			wb.formats[binary.LittleEndian.Uint16(r.data)] = format
+		case _xlsRecordXF:
+			if len(r.data) < 4 {
+				return nil
+			}
+			wb.xfFormats = append(wb.xfFormats, binary.LittleEndian.Uint16(r.data[2:]))
+		case _xlsRecordSST:
+			sstSegments = append(sstSegments, r.data)
+		case _xlsRecordContinue:
+			if lastType == _xlsRecordSST {
+				sstSegments = append(sstSegments, r.data)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if len(sstSegments) != 0 {
+		wb.sst, err = readXLSSST(sstSegments)
+	}
+	return err
+}
+
+func (wb *xlsWorkbook) parseSheet(f *excelize.File, boundSheet xlsBoundSheet) error {
+	sheet := boundSheet.name
+	setCell := func(row, col uint16, value interface{}) error {
+		axis, err := excelize.CoordinatesToCellName(int(col)+1, int(row)+1)
+		if err != nil {
+			return err
+		}
+		return f.SetCellValue(sheet, axis, value)
+	}
+
+	// the cell of the last FORMULA record whose string value is in the next STRING record
+	var formulaRow, formulaCol uint16
+	var formulaString bool
+	return wb.readRecords(int(boundSheet.offset), func(r *xlsRecord) error {
+		d := r.data
+		switch r.typ {
+		case _xlsRecordLabelSST:
+			if len(d) < 10 {
+				return nil
+			}
+			idx := binary.LittleEndian.Uint32(d[6:])
+			if int(idx) >= len(wb.sst) {
+				return errors.Errorf("xls sst index %d is out of range", idx)
+			}
+			return setCell(binary.LittleEndian.Uint16(d), binary.LittleEndian.Uint16(d[2:]), wb.sst[idx])
+		case _xlsRecordLabel:
+			if len(d) < 8 {
+				return nil
+			}
+			s, _ := readXLSString(d[6:])
+			return setCell(binary.LittleEndian.Uint16(d), binary.LittleEndian.Uint16(d[2:]), s)
+		case _xlsRecordNumber:
+			if len(d) < 14 {
+				return nil
+			}
+			value := math.Float64frombits(binary.LittleEndian.Uint64(d[6:]))
+			return setCell(binary.LittleEndian.Uint16(d), binary.LittleEndian.Uint16(d[2:]), wb.numberValue(binary.LittleEndian.Uint16(d[4:]), value))
+		case _xlsRecordRK:
+			if len(d) < 10 {
+				return nil
+			}
+			value := xlsRKValue(binary.LittleEndian.Uint32(d[6:]))
+			return setCell(binary.LittleEndian.Uint16(d), binary.LittleEndian.Uint16(d[2:]), wb.numberValue(binary.LittleEndian.Uint16(d[4:]), value))
+		case _xlsRecordMulRK:
+			if len(d) < 6 {
+				return nil
+			}
+			row, col := binary.LittleEndian.Uint16(d), binary.LittleEndian.Uint16(d[2:])
+			for i := 4; i+6 <= len(d)-2; i += 6 {
+				value := xlsRKValue(binary.LittleEndian.Uint32(d[i+2:]))
+				if err := setCell(row, col, wb.numberValue(binary.LittleEndian.Uint16(d[i:]), value)); err != nil {
+					return err
+				}
+				col++
+			}
+		case _xlsRecordBoolErr:
+			if len(d) < 8 || d[7] != 0 {
+				return nil
+			}
+			return setCell(binary.LittleEndian.Uint16(d), binary.LittleEndian.Uint16(d[2:]), d[6] != 0)
+		case _xlsRecordFormula:
+			if len(d) < 14 {
+				return nil
+			}
+			row, col := binary.LittleEndian.Uint16(d), binary.LittleEndian.Uint16(d[2:])
+			result := d[6:14]
+			if binary.LittleEndian.Uint16(result[6:]) != 0xFFFF {
+				value := math.Float64frombits(binary.LittleEndian.Uint64(result))
+				return setCell(row, col, wb.numberValue(binary.LittleEndian.Uint16(d[4:]), value))
+			}
+			switch result[0] {
+			case 0:
+				formulaRow, formulaCol, formulaString = row, col, true
+			case 1:
+				return setCell(row, col, result[2] != 0)
+			}
+		case _xlsRecordString:
+			if !formulaString {
+				return nil
+			}
+			formulaString = false
+			s, _ := readXLSString(d)
+			return setCell(formulaRow, formulaCol, s)
+		case _xlsRecordMergedCells:
+			if len(d) < 2 {
+				return nil
+			}
+			count := int(binary.LittleEndian.Uint16(d))
+			for i := 0; i < count && 2+i*8+8 <= len(d); i++ {
+				ref := d[2+i*8:]
+				rowFirst, rowLast := binary.LittleEndian.Uint16(ref), binary.LittleEndian.Uint16(ref[2:])
+				colFirst, colLast := binary.LittleEndian.Uint16(ref[4:]), binary.LittleEndian.Uint16(ref[6:])
+				hCell, err := excelize.CoordinatesToCellName(int(colFirst)+1, int(rowFirst)+1)
+				if err != nil {
+					return err
+				}
+				vCell, err := excelize.CoordinatesToCellName(int(colLast)+1, int(rowLast)+1)
+				if err != nil {
+					return err
+				}
+				if err = f.MergeCell(sheet, hCell, vCell); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+}
+
+/*
+*
+numberValue return the cell value of a number, a date formatted number is turned to text of date,
+so that TimeField can translate it
+*/
+func (wb *xlsWorkbook) numberValue(xf uint16, value float64) interface{} {
+	if int(xf) >= len(wb.xfFormats) || !wb.isDateFormat(wb.xfFormats[xf]) {
+		return value
+	}
+
+	t, err := excelize.ExcelDateToTime(value, wb.date1904)
+	if err != nil {
+		return value
+	}
+	if t.Equal(t.Truncate(24 * time.Hour)) {
+		return t.Format(_dateLayout)
+	}
+	return t.Format(_dateLayout + " 15:04:05")
+}
+
+func (wb *xlsWorkbook) isDateFormat(ifmt uint16) bool {
+	// built-in date formats
+	if (ifmt >= 14 && ifmt <= 22) || (ifmt >= 45 && ifmt <= 47) {
+		return true
+	}
+	format, ok := wb.formats[ifmt]
+	if !ok {
+		return false
+	}
+
+	// ignore the quoted text and the colors, ex: [Red]"year"yyyy
+	var inQuote, inBracket bool
+	for _, r := range strings.ToLower(format) {
+		switch {
+		case r == '"':
+			inQuote = !inQuote
+		case inQuote:
+		case r == '[':
+			inBracket = true
+		case r == ']':
+			inBracket = false
+		case inBracket:
+		case r == 'y' || r == 'd' || r == 'm' || r == 'h' || r == 's':
+			return true
+		}
+	}
+	return false
+}
+
+/*
+*
+xlsRKValue decode a RK number
+*/
+func xlsRKValue(rk uint32) float64 {
+	var value float64
+	if rk&0x02 != 0 {
+		value = float64(int32(rk) >> 2)
+	} else {
+		value = math.Float64frombits(uint64(rk&0xFFFFFFFC) << 32)
+	}
+	if rk&0x01 != 0 {
+		value /= 100
+	}
+	return value
+}
+
+/*
+*
+readXLSShortString read a ShortXLUnicodeString, which has a 1 byte char count
+*/
+func readXLSShortString(d []byte) (string, int) {
+	if len(d) < 2 {
+		return "", len(d)
+	}
+	return readXLSChars(d[2:], int(d[0]), d[1]&0x01 != 0, 2)
+}
+
+/*
+*
+readXLSString read a XLUnicodeString, which has a 2 bytes char count
+*/
+func readXLSString(d []byte) (string, int) {
+	if len(d) < 3 {
+		return "", len(d)
+	}
+	return readXLSChars(d[3:], int(binary.LittleEndian.Uint16(d)), d[2]&0x01 != 0, 3)
+}
+
+func readXLSChars(d []byte, count int, highByte bool, headerSize int) (string, int) {
+	size := count
+	if highByte {
+		size *= 2
+	}
+	size = min(size, len(d))
+	return decodeXLSChars(d[:size], highByte), headerSize + size
+}
+
+func decodeXLSChars(d []byte, highByte bool) string {
+	if !highByte {
+		// the compressed chars are the low bytes of UTF-16
+		runes := make([]rune, len(d))
+		for i, b := range d {
+			runes[i] = rune(b)
+		}
+		return string(runes)
+	}
+	units := make([]uint16, len(d)/2)
+	for i := range units {
+		units[i] = binary.LittleEndian.Uint16(d[i*2:])
+	}
+	return string(utf16.Decode(units))
+}
+
+/*
+*
+xlsSegmentReader read bytes across the SST record and its CONTINUE records
+*/
+type xlsSegmentReader struct {
+	segments [][]byte
+	seg, pos int
+}
+
+func (r *xlsSegmentReader) next() bool {
+	for r.pos >= len(r.segments[r.seg]) {
+		if r.seg+1 >= len(r.segments) {
+			return false
+		}
+		r.seg++
+		r.pos = 0
+	}
+	return true
+}
+
+func (r *xlsSegmentReader) bytes(n int) ([]byte, error) {
+	res := make([]byte, 0, n)
+	for len(res) < n {
+		if !r.next() {
+			return nil, io.ErrUnexpectedEOF
+		}
+		seg := r.segments[r.seg]
+		m := min(n-len(res), len(seg)-r.pos)
+		res = append(res, seg[r.pos:r.pos+m]...)
+		r.pos += m
+	}
+	return res, nil
+}
+
+/*
+*
+skip skip n bytes without reading them, n may be large since it's from the file
+*/
+func (r *xlsSegmentReader) skip(n int) error {
+	for n > 0 {
+		if !r.next() {
+			return io.ErrUnexpectedEOF
+		}
+		m := min(n, len(r.segments[r.seg])-r.pos)
+		r.pos += m
+		n -= m
+	}
+	return nil
+}
+
+func (r *xlsSegmentReader) uint16() (uint16, error) {
+	b, err := r.bytes(2)
+	if err != nil {
+		return 0, err
+	}
+	return binary.LittleEndian.Uint16(b), nil
+}
+
+func (r *xlsSegmentReader) uint32() (uint32, error) {
+	b, err := r.bytes(4)
+	if err != nil {
+		return 0, err
+	}
+	return binary.LittleEndian.Uint32(b), nil
+}
+
+/*
+*
+chars read count chars, when the chars are split by a CONTINUE record, the record begins with a new option flags byte
+*/
+func (r *xlsSegmentReader) chars(count int, highByte bool) (string, error) {
+	var b strings.Builder
+	for count > 0 {
+		if r.pos >= len(r.segments[r.seg]) {
+			if r.seg+1 >= len(r.segments) {
+				return "", io.ErrUnexpectedEOF
+			}
+			r.seg++
+			r.pos = 0
+			flags, err := r.bytes(1)
+			if err != nil {
+				return "", err
+			}
+			highByte = flags[0]&0x01 != 0
+		}
+
+		charSize := 1
+		if highByte {
+			charSize = 2
+		}
+		n := min(count, (len(r.segments[r.seg])-r.pos)/charSize)
+		if n == 0 {
+			return "", errors.New("xls string is split in a char")
+		}
+		b.WriteString(decodeXLSChars(r.segments[r.seg][r.pos:r.pos+n*charSize], highByte))
+		r.pos += n * charSize
+		count -= n
+	}
+	return b.String(), nil
+}
+
+/*
+*
+readXLSSST read the shared strings table
+*/
+func readXLSSST(segments [][]byte) (sst []string, err error) {
+	r := &xlsSegmentReader{segments: segments}
+	if _, err = r.uint32(); err != nil {
+		return
+	}
+	unique, err := r.uint32()
+	if err != nil {
+		return
+	}
+
+	// unique is from the file, the capacity is limited by the remaining bytes, every string takes 3 bytes at least
+	var remaining int
+	for _, segment := range segments {
+		remaining += len(segment)
+	}
+	sst = make([]string, 0, min(unique, uint32(max(remaining-8, 0)/3)))
+	for i := uint32(0); i < unique; i++ {
+		var count uint16
+		if count, err = r.uint16(); err != nil {
+			return nil, errors.Wrapf(err, "read sst string %d", i)
+		}
+		var flags []byte
+		if flags, err = r.bytes(1); err != nil {
+			return nil, err
+		}
+
+		var runs uint16
+		var extSize uint32
+		if flags[0]&0x08 != 0 {
+			if runs, err = r.uint16(); err != nil {
+				return nil, err
+			}
+		}
+		if flags[0]&0x04 != 0 {
+			if extSize, err = r.uint32(); err != nil {
+				return nil, err
+			}
+		}
+
+		var s string
+		if s, err = r.chars(int(count), flags[0]&0x01 != 0); err != nil {
+			return nil, errors.Wrapf(err, "read sst string %d", i)
+		}
+		if err = r.skip(int(runs)*4 + int(extSize)); err != nil {
+			return nil, err
+		}
+		sst = append(sst, s)
+	}
+	return
+}
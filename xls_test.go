@@ -0,0 +1,96 @@
+package excel
+
+import (
+	"encoding/binary"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+/*
+*
+testdata/contract.xls is a BIFF8 workbook with a sheet 合同:
+the header 合同 is merged over A1:D1 and the leaves 编号 名称 金额 日期 are single merge cells at row 2,
+B3 is a shared string of 5000 chars which is split by a CONTINUE record of SST,
+C3 is NUMBER, D3 and D4 are date formatted, C4:D4 is MULRK, C5 is RK divided by 100
+*/
+const _xlsFixture = "testdata/contract.xls"
+
+type xlsContract struct {
+	Number StringField `ex:"合同|编号"`
+	Name   StringField `ex:"合同|名称"`
+	Amount FloatField  `ex:"合同|金额"`
+	Date   TimeField   `ex:"合同|日期"`
+}
+
+func TestOpenXLS(t *testing.T) {
+	open := map[string]func() (*Excel, error){
+		"file": func() (*Excel, error) { return NewExcelFromFile(_xlsFixture) },
+		"reader": func() (*Excel, error) {
+			f, err := os.Open(_xlsFixture)
+			if err != nil {
+				return nil, err
+			}
+			defer f.Close()
+			return NewExcelFromReader(f)
+		},
+	}
+	for name, fn := range open {
+		t.Run(name, func(t *testing.T) {
+			e, err := fn()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if sheets := e.file.GetSheetList(); !reflect.DeepEqual(sheets, []string{"合同"}) {
+				t.Fatalf("unexpected sheets %v", sheets)
+			}
+
+			mergeCells, err := e.file.GetMergeCells("合同")
+			if err != nil {
+				t.Fatal(err)
+			}
+			var refs []string
+			for _, mergeCell := range mergeCells {
+				refs = append(refs, mergeCell.GetStartAxis()+":"+mergeCell.GetEndAxis())
+			}
+			if want := []string{"A1:D1", "A2:A2", "B2:B2", "C2:C2", "D2:D2"}; !reflect.DeepEqual(refs, want) {
+				t.Fatalf("expect merge cells %v, got %v", want, refs)
+			}
+
+			rows, err := e.GetRowsWithoutHeader()
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := [][]string{
+				{"HT-001", strings.Repeat("长", 5000), "1024.5", "2024-01-01"},
+				{"HT-002", "短名称", "20", "2024-01-02"},
+				{"HT-003", "短名称", "3.5"},
+			}
+			if !reflect.DeepEqual(rows, want) {
+				t.Fatalf("unexpected rows %q", rows)
+			}
+
+			contract := &xlsContract{}
+			if _, err = e.ScanExRow(rows[0], contract); err != nil {
+				t.Fatal(err)
+			}
+			if contract.Amount.GetValue() != 1024.5 || contract.Date.GetValue().(time.Time).Format(_dateLayout) != "2024-01-01" {
+				t.Fatalf("unexpected scanned row %+v", contract)
+			}
+		})
+	}
+}
+
+func TestReadXLSSSTCapacity(t *testing.T) {
+	// the unique count claims 4 billion strings, but the record holds only one
+	segment := binary.LittleEndian.AppendUint32(nil, 1)
+	segment = binary.LittleEndian.AppendUint32(segment, 0xFFFFFFFF)
+	segment = append(segment, 2, 0, 0, 'o', 'k')
+
+	sst, err := readXLSSST([][]byte{segment})
+	if err == nil {
+		t.Fatalf("expect error of the truncated sst, got %q", sst)
+	}
+}